@@ -0,0 +1,69 @@
+package ladder
+
+/*
+ * adjacency.go -- the pairs of every word in compressed sparse row form
+ */
+
+// An adjacency lists the pairs of every word in compressed sparse row (CSR) form:
+// the words linked to word w are link[first[w]:first[w+1]]. Two arrays take the
+// place of a separately allocated list, and slice header, for each word, and keep
+// the pairs of words numbered near each other near each other in memory.
+type adjacency struct {
+	first Indexes // where the pairs of each word start in link, and then where the last end
+	link  Indexes
+}
+
+// Pack the pairs of each word into an adjacency.
+func newAdjacency(pair []Indexes) adjacency {
+	links := 0
+	for _, p := range pair {
+		links += len(p)
+	}
+	a := adjacency{first: make(Indexes, len(pair)+1), link: make(Indexes, 0, links)}
+	for w, p := range pair {
+		a.link = append(a.link, p...)
+		a.first[w+1] = Index(len(a.link))
+	}
+	return a
+}
+
+// the number of words
+func (a adjacency) words() int { return len(a.first) - 1 }
+
+// the words linked to w, in order
+func (a adjacency) of(w Index) Indexes { return a.link[a.first[w]:a.first[w+1]:a.first[w+1]] }
+
+// the number of words linked to w
+func (a adjacency) degree(w Index) int { return int(a.first[w+1] - a.first[w]) }
+
+// Number the words of the components afresh, those of each component together and
+// in the same order, and return the adjacency of the words in these numbers along
+// with the components, whose words are then the numbers from one to the next. The
+// search from each word of a component then reads one run of each array rather
+// than words scattered through the dictionary. Lists of results by position within
+// a component are unchanged.
+func relabel(adj adjacency, component []Component) (adjacency, []Component) {
+	label := make(Indexes, adj.words())  // new number of each word
+	number := make(Indexes, adj.words()) // the new numbers in order, shared by the components
+	renumbered := make([]Component, len(component))
+	n := 0
+	for cn, c := range component {
+		for i, w := range c.word {
+			label[w] = Index(n + i)
+			number[n+i] = Index(n + i)
+		}
+		renumbered[cn] = Component{number[n : n+c.words : n+c.words], c.words}
+		n += c.words
+	}
+
+	a := adjacency{first: make(Indexes, adj.words()+1), link: make(Indexes, 0, len(adj.link))}
+	for _, c := range component {
+		for _, w := range c.word {
+			for _, wn := range adj.of(w) {
+				a.link = append(a.link, label[wn])
+			}
+			a.first[label[w]+1] = Index(len(a.link))
+		}
+	}
+	return a, renumbered
+}
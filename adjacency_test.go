@@ -0,0 +1,54 @@
+package ladder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAdjacency(t *testing.T) {
+	g, err := ReadGraph([]string{"words/webster-4"}, &Options{Length: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pair := make([]Indexes, g.Len())
+	for w := range pair {
+		pair[w] = g.Neighbors(Index(w))
+		if g.adj.degree(Index(w)) != len(pair[w]) || len(g.EdgeTags(Index(w))) != len(pair[w]) {
+			t.Fatalf("%s: expected %d links and tags", g.word[w], len(pair[w]))
+		}
+	}
+	if !reflect.DeepEqual(newAdjacency(pair), g.adj) {
+		t.Fatalf("adjacency differs from its lists")
+	}
+
+	// each component's words are numbered together, in order, with the same links
+	component := g.Components()
+	adj, renumbered := relabel(g.adj, component)
+	next := Index(0)
+	for cn, c := range renumbered {
+		old := component[cn]
+		if c.words != old.words {
+			t.Fatalf("component %d: expected %d words, found %d", cn, old.words, c.words)
+		}
+		label := make(map[Index]Index)
+		for i, w := range c.word {
+			if w != next {
+				t.Fatalf("component %d: expected word %d, found %d", cn, next, w)
+			}
+			label[old.word[i]] = w
+			next++
+		}
+		for i, w := range old.word {
+			p := Indexes{}
+			for _, wn := range pair[w] {
+				p = append(p, label[wn])
+			}
+			if !reflect.DeepEqual(adj.of(c.word[i]), p) {
+				t.Errorf("%s: expected links %v, found %v", g.word[w], p, adj.of(c.word[i]))
+			}
+		}
+	}
+	if adj.words() != int(next) || adj.words() != g.Len() {
+		t.Errorf("expected %d words, found %d", g.Len(), adj.words())
+	}
+}
@@ -0,0 +1,134 @@
+package ladder
+
+/*
+ * articulation.go -- which words and steps hold each component together
+ */
+
+import "sort"
+
+// Cuts describes how fragile a component is. Removing an articulation word, or
+// a bridge between two words, splits the component in two or more, so that some
+// ladders are no longer possible. The blocks are its biconnected components: the
+// maximal parts that no single removal splits, each bridge being a block of two
+// and a word without pairs a block of one.
+type Cuts struct {
+	Articulation Indexes    // words whose removal splits the component, in word order
+	Bridge       [][2]Index // steps whose removal splits the component, lesser word first, in order
+	Blocks       int        // number of biconnected components
+}
+
+// Cuts returns the articulation words, bridges, and number of biconnected blocks of
+// component cn (a position in the list returned by Components).
+func (g *Graph) Cuts(cn int) Cuts {
+	return newCutFinder(len(g.word)).find(g.adj, g.Components()[cn])
+}
+
+// Scratch space for Tarjan's depth first search, reusable from one component to the
+// next. The order of a word is when the search discovered it, counting from one, and
+// its low point is the earliest order reachable from it by descending the search
+// tree and then taking a single step back toward the root.
+type cutFinder struct {
+	order  Indexes
+	low    Indexes
+	parent Indexes
+	stack  []cutFrame
+	cut    []bool
+}
+
+// a word on the search stack and the position of the next of its pairs to explore
+type cutFrame struct {
+	word Index
+	next int
+}
+
+func newCutFinder(words int) *cutFinder {
+	return &cutFinder{
+		order:  make(Indexes, words),
+		low:    make(Indexes, words),
+		parent: make(Indexes, words),
+		cut:    make([]bool, words),
+	}
+}
+
+// Find the cuts of component c by an iterative depth first search, since a
+// recursive one could be as deep as the component is large.
+func (f *cutFinder) find(adj adjacency, c Component) Cuts {
+	var cuts Cuts
+	if c.words == 0 {
+		return cuts
+	}
+	if c.words == 1 { // a lone word is a block by itself
+		cuts.Blocks = 1
+		return cuts
+	}
+	for _, w := range c.word {
+		f.order[w] = 0 // not yet discovered
+		f.cut[w] = false
+	}
+
+	root := c.word[0]
+	clock := Index(1)
+	f.order[root] = clock
+	f.low[root] = clock
+	f.parent[root] = INFINITY
+	f.stack = append(f.stack[:0], cutFrame{root, 0})
+	children := 0 // of the root in the search tree
+
+	for len(f.stack) > 0 {
+		top := &f.stack[len(f.stack)-1]
+		n := top.word
+		if top.next < adj.degree(n) {
+			wn := adj.of(n)[top.next]
+			top.next++
+			switch {
+			case f.order[wn] == 0: // descend to an undiscovered word
+				clock++
+				f.order[wn] = clock
+				f.low[wn] = clock
+				f.parent[wn] = n
+				f.stack = append(f.stack, cutFrame{wn, 0})
+			case wn != f.parent[n]: // step back to a word found earlier
+				f.low[n] = minIndex(f.low[n], f.order[wn])
+			}
+			continue
+		}
+
+		// every pair of n explored, so return to its parent
+		f.stack = f.stack[:len(f.stack)-1]
+		p := f.parent[n]
+		if p == INFINITY {
+			continue
+		}
+		f.low[p] = minIndex(f.low[p], f.low[n])
+		if f.low[n] > f.order[p] { // nothing below n reaches p or above but through it
+			cuts.Bridge = append(cuts.Bridge, orderedPair(p, n))
+		}
+		if f.low[n] >= f.order[p] { // n and its descendants complete a block with p
+			cuts.Blocks++
+			if p == root {
+				children++
+			} else {
+				f.cut[p] = true
+			}
+		}
+	}
+	f.cut[root] = children > 1
+
+	for _, w := range c.word {
+		if f.cut[w] {
+			cuts.Articulation = append(cuts.Articulation, w)
+		}
+	}
+	sort.Slice(cuts.Bridge, func(i, j int) bool {
+		a, b := cuts.Bridge[i], cuts.Bridge[j]
+		return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+	})
+	return cuts
+}
+
+func orderedPair(a, b Index) [2]Index {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]Index{a, b}
+}
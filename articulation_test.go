@@ -0,0 +1,124 @@
+package ladder
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// connected reports whether the words of component c other than word skip remain
+// connected without the step from a to b
+func connected(pair []Indexes, c Component, skip Index, a, b Index) bool {
+	seen := make(map[Index]bool)
+	var queue Indexes
+	for _, w := range c.word {
+		if w != skip {
+			seen[w] = true
+			queue = append(queue, w)
+			break
+		}
+	}
+	for head := 0; head < len(queue); head++ {
+		n := queue[head]
+		for _, wn := range pair[n] {
+			if wn == skip || seen[wn] || (n == a && wn == b) || (n == b && wn == a) {
+				continue
+			}
+			seen[wn] = true
+			queue = append(queue, wn)
+		}
+	}
+	if skip == INFINITY {
+		return len(queue) == c.words
+	}
+	return len(queue) == c.words-1
+}
+
+// reference cuts by removing each word and each step in turn
+func bruteCuts(pair []Indexes, c Component) Cuts {
+	var cuts Cuts
+	for _, w := range c.word {
+		if !connected(pair, c, w, INFINITY, INFINITY) {
+			cuts.Articulation = append(cuts.Articulation, w)
+		}
+	}
+	for _, w := range c.word {
+		for _, wn := range pair[w] {
+			if w < wn && !connected(pair, c, INFINITY, w, wn) {
+				cuts.Bridge = append(cuts.Bridge, [2]Index{w, wn})
+			}
+		}
+	}
+	return cuts
+}
+
+// two cycles of n words joined by a path of m steps from word 0 of one to word 0
+// of the other, so that its blocks are the two cycles and the m bridges
+func buildBarbellGraph(n, m int) ([]string, []Indexes, []Component) {
+	words := 2*n + m - 1
+	node, component := buildGraph(words)
+	a := make([]Indexes, words)
+	link := func(u, v int) {
+		a[u] = append(a[u], Index(v))
+		a[v] = append(a[v], Index(u))
+	}
+	for i := 0; i < n; i++ {
+		link(i, (i+1)%n)
+		link(n+i, n+(i+1)%n)
+	}
+	prev := 0
+	for i := 0; i < m-1; i++ {
+		link(prev, 2*n+i)
+		prev = 2*n + i
+	}
+	link(prev, n)
+	for w := range a {
+		sort.Sort(a[w])
+	}
+	return node, a, component
+}
+
+func TestCuts(t *testing.T) {
+	blocks := map[string]int{"path": 49, "cycle": 1, "wheel": 1, "grid": 1, "bipartite": 1, "tree": 62}
+	for _, test := range weightedGraphs {
+		node, a, component := test.build()
+		cuts := newCutFinder(len(node)).find(newAdjacency(a), component[0])
+		expected := bruteCuts(a, component[0])
+		if !reflect.DeepEqual(cuts.Articulation, expected.Articulation) || !reflect.DeepEqual(cuts.Bridge, expected.Bridge) {
+			t.Errorf("%s: expected %+v, found %+v", test.name, expected, cuts)
+		}
+		if b, ok := blocks[test.name]; !ok || cuts.Blocks != b {
+			t.Errorf("%s: expected %d blocks, found %d", test.name, b, cuts.Blocks)
+		}
+	}
+
+	// the cut words of a barbell are the ends of the path and the words along it
+	const n, m = 7, 4
+	barbell := namedGraph(func() ([]string, []Indexes, []Component) { return buildBarbellGraph(n, m) })
+	cuts := barbell.Cuts(0)
+	articulation := Indexes{0, n, 2 * n, 2*n + 1, 2*n + 2}
+	bridge := [][2]Index{{0, 2 * n}, {n, 2*n + 2}, {2 * n, 2*n + 1}, {2*n + 1, 2*n + 2}}
+	if !reflect.DeepEqual(cuts.Articulation, articulation) || !reflect.DeepEqual(cuts.Bridge, bridge) || cuts.Blocks != m+2 {
+		t.Errorf("barbell: expected %v, %v, and %d blocks, found %+v", articulation, bridge, m+2, cuts)
+	}
+
+	// a single step is a bridge but neither word is an articulation
+	pair := namedGraph(func() ([]string, []Indexes, []Component) { return buildPathGraph(2) })
+	if cuts := pair.Cuts(0); cuts.Articulation != nil || len(cuts.Bridge) != 1 || cuts.Blocks != 1 {
+		t.Errorf("pair: expected one bridge and block, found %+v", cuts)
+	}
+
+	// a word without pairs is a block of its own
+	lone := namedGraph(func() ([]string, []Indexes, []Component) { return buildPathGraph(1) })
+	if cuts := lone.Cuts(0); cuts.Articulation != nil || cuts.Bridge != nil || cuts.Blocks != 1 {
+		t.Errorf("lone word: expected one block, found %+v", cuts)
+	}
+	g, err := NewGraph([]string{"cat", "cot", "dog"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dog, _ := g.Find("dog")
+	if cuts := g.Cuts(componentOf(g.Components(), dog)); cuts.Blocks != 1 {
+		t.Errorf("dog: expected one block, found %+v", cuts)
+	}
+}
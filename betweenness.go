@@ -0,0 +1,157 @@
+package ladder
+
+/*
+ * betweenness.go -- which words lie on the most shortest ladders
+ */
+
+import "sort"
+
+// A Bottleneck is a word and its betweenness centrality: the number of shortest
+// ladders between other pairs of words that pass through it, where the ladders
+// of each pair share a total of one among them.
+type Bottleneck struct {
+	Word        Index
+	Betweenness float64
+}
+
+// Bottlenecks returns the k words of component cn (a position in the list returned
+// by Components) of greatest betweenness, greatest first and then in word order, or
+// every word of the component when k is negative.
+func (g *Graph) Bottlenecks(cn, k int) []Bottleneck {
+	c := g.Components()[cn]
+	betweenness := findBetweennessV2(g.word, g.adj, c)
+	list := make([]Bottleneck, c.words)
+	for i, w := range c.word {
+		list[i] = Bottleneck{w, betweenness[i]}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Betweenness > list[j].Betweenness })
+	if k >= 0 && k < len(list) {
+		list = list[:k]
+	}
+	return list
+}
+
+// Variant of ssspBFSAll for Brandes' algorithm. It counts the shortest paths from w
+// to each node of the component in sigma, as floating point since the counts can
+// be astronomical, then visits the nodes in order of decreasing distance to find
+// each one's dependency, the share of the shortest paths from w to farther nodes
+// that pass through it, adding that to its betweenness.
+func ssspBFSBrandes(word []Index, adj adjacency, w Index, distance, queue []Index, done []bool, sigma, delta, betweenness []float64) {
+	for _, wn := range word {
+		distance[wn] = INFINITY // not known to be rechable (can be graph or component)
+		done[wn] = false        // not yet discovered and processed by traversal
+		sigma[wn] = 0           // no paths known yet
+		delta[wn] = 0           // no dependency known yet
+	}
+	distance[w] = 0
+	done[w] = true
+	sigma[w] = 1
+
+	// push starting word onto queue
+	var head, tail int
+	queue[tail] = w
+	tail++
+
+	// breadth first traversal of graph rooted at w
+	for head < tail { // while queue is not empty
+		n := queue[head]
+		head++
+		d := distance[n] + 1
+		for _, wn := range adj.of(n) {
+			switch {
+			case !done[wn]:
+				done[wn] = true
+				distance[wn] = d
+				sigma[wn] = sigma[n]
+				queue[tail] = wn
+				tail++
+			case distance[wn] == d: // another shortest path to wn
+				sigma[wn] += sigma[n]
+			}
+		}
+	}
+
+	// accumulate dependencies from the farthest nodes back toward w
+	for i := tail - 1; i > 0; i-- {
+		n := queue[i]
+		d := distance[n] + 1
+		for _, wn := range adj.of(n) {
+			if distance[wn] == d { // n precedes wn on shortest paths from w
+				delta[n] += sigma[n] / sigma[wn] * (1 + delta[wn])
+			}
+		}
+		betweenness[n] += delta[n]
+	}
+}
+
+// Find the betweenness of each word of component c, in the order of its words.
+func findBetweennessV1(word []string, adj adjacency, c Component) []float64 {
+	distance := make([]Index, len(word))
+	queue := make([]Index, c.words)
+	done := make([]bool, len(word))
+	sigma := make([]float64, len(word))
+	delta := make([]float64, len(word))
+	sum := make([]float64, len(word))
+	for _, w := range c.word {
+		ssspBFSBrandes(c.word, adj, w, distance, queue, done, sigma, delta, sum)
+	}
+	return componentBetweenness(c, sum)
+}
+
+// Parallel version of findBetweennessV1. Each worker accumulates the betweenness
+// found by its searches and sends the sums when done.
+func findBetweennessV2(word []string, adj adjacency, c Component) []float64 {
+	// optimization -- skip parallel framework overhead for small components
+	if c.words < BREAKPOINT {
+		return findBetweennessV1(word, adj, c)
+	}
+
+	tasks := make(chan Index)
+	results := make(chan []float64)
+
+	// start workers
+	workers := minInt(c.words, MaxProcs)
+	for i := 0; i < workers; i++ {
+		go func(id int, in chan Index, out chan []float64) {
+			distance := make(Indexes, len(word))
+			queue := make(Indexes, len(c.word))
+			done := make([]bool, len(word))
+			sigma := make([]float64, len(word))
+			delta := make([]float64, len(word))
+			sum := make([]float64, len(word))
+			for w := range in {
+				ssspBFSBrandes(c.word, adj, w, distance, queue, done, sigma, delta, sum)
+			}
+			out <- sum
+		}(i, tasks, results)
+	}
+
+	// start dispatcher
+	go func(out chan Index) {
+		for _, w := range c.word {
+			out <- w
+		}
+		close(out)
+	}(tasks)
+
+	// harvest results from workers
+	total := make([]float64, len(word))
+	for i := 0; i < workers; i++ {
+		sum := <-results
+		for _, w := range c.word {
+			total[w] += sum[w]
+		}
+	}
+	close(results)
+	return componentBetweenness(c, total)
+}
+
+// gather the betweenness of each word of component c from sums indexed by word,
+// halving them since each unordered pair's paths were followed from both ends
+func componentBetweenness(c Component, sum []float64) []float64 {
+	betweenness := make([]float64, c.words)
+	for i, w := range c.word {
+		betweenness[i] = sum[w] / 2
+	}
+	return betweenness
+}
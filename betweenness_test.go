@@ -0,0 +1,70 @@
+package ladder
+
+import (
+	"testing"
+)
+
+// reference betweenness by counting shortest paths between every pair: word v lies
+// on sigma(s,v) sigma(v,t) of the sigma(s,t) shortest paths from s to t when
+// d(s,v) + d(v,t) = d(s,t)
+func pathCountBetweenness(word []string, pair []Indexes, c Component) []float64 {
+	n := len(word)
+	adj := newAdjacency(pair)
+	distance := make([][]Index, n)
+	sigma := make([][]float64, n)
+	queue := make([]Index, c.words)
+	done := make([]bool, n)
+	count := make([]uint64, n)
+	for _, s := range c.word {
+		distance[s] = make([]Index, n)
+		sigma[s] = make([]float64, n)
+		ssspBFSAll(c.word, adj, s, distance[s], queue, done, count)
+		for _, t := range c.word {
+			sigma[s][t] = float64(count[t])
+		}
+	}
+	betweenness := make([]float64, c.words)
+	for i, v := range c.word {
+		for _, s := range c.word {
+			for _, t := range c.word {
+				if s < t && s != v && t != v && distance[s][v]+distance[v][t] == distance[s][t] {
+					betweenness[i] += sigma[s][v] * sigma[v][t] / sigma[s][t]
+				}
+			}
+		}
+	}
+	return betweenness
+}
+
+func TestBetweenness(t *testing.T) {
+	// path: word i lies on the paths between the i words before it and n-1-i after
+	const n = 20
+	node, a, component := buildPathGraph(n)
+	for version, betweenness := range [][]float64{findBetweennessV1(node, newAdjacency(a), component[0]), findBetweennessV2(node, newAdjacency(a), component[0])} {
+		for i, b := range betweenness {
+			if !closeTo(b, float64(i*(n-1-i))) {
+				t.Errorf("path V%d: word %d expected %d, found %g", version+1, i, i*(n-1-i), b)
+			}
+		}
+	}
+
+	// star: the hub lies on the only path between each pair of leaves
+	star := namedGraph(func() ([]string, []Indexes, []Component) { return buildStarGraph(n) })
+	top := star.Bottlenecks(0, 2)
+	if len(top) != 2 || top[0].Word != n-1 || top[0].Betweenness != (n-1)*(n-2)/2 || top[1].Word != 0 || top[1].Betweenness != 0 {
+		t.Errorf("star: expected hub with %d then leaf 0 with 0, found %+v", (n-1)*(n-2)/2, top)
+	}
+
+	for _, test := range weightedGraphs {
+		node, a, component := test.build()
+		expected := pathCountBetweenness(node, a, component[0])
+		for version, betweenness := range [][]float64{findBetweennessV1(node, newAdjacency(a), component[0]), findBetweennessV2(node, newAdjacency(a), component[0])} {
+			for i := range betweenness {
+				if !closeTo(betweenness[i], expected[i]) {
+					t.Errorf("%s V%d: word %d expected %g, found %g", test.name, version+1, i, expected[i], betweenness[i])
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package ladder
+
+/*
+ * centrality.go -- which words are nearest to all the others
+ */
+
+import "sort"
+
+// Centrality measures how near a word is to the other words of its component, on
+// a scale where one means adjacent to every one of them. Words in components of
+// their own have centralities of zero.
+type Centrality struct {
+	Word      Index
+	Closeness float64 // n-1 over the summed distances to the n-1 other words of its component
+	Harmonic  float64 // mean of the reciprocal distances to the other words of its component
+}
+
+// Centrality returns the closeness and harmonic centrality of each word of component
+// cn (a position in the list returned by Components), most central first: by
+// descending closeness, then harmonic centrality, then in word order.
+func (g *Graph) Centrality(cn int) []Centrality {
+	central := findCentralityV2(g.word, g.adj, g.Components()[cn])
+	sort.SliceStable(central, func(i, j int) bool {
+		a, b := central[i], central[j]
+		if a.Closeness != b.Closeness {
+			return a.Closeness > b.Closeness
+		}
+		return a.Harmonic > b.Harmonic
+	})
+	return central
+}
+
+// Find the centrality of word w from a BFS of component c that found the summed
+// distances from w to be total, counting the words at each distance in histogram,
+// which is cleared for the next search.
+func centrality(c Component, w Index, total, farthest int, histogram []int) Centrality {
+	x := Centrality{Word: w}
+	if c.words > 1 {
+		x.Closeness = float64(c.words-1) / float64(total)
+		for d := 1; d <= farthest; d++ {
+			x.Harmonic += float64(histogram[d]) / float64(d)
+		}
+		x.Harmonic /= float64(c.words - 1)
+	}
+	for d := 0; d <= farthest; d++ {
+		histogram[d] = 0
+	}
+	return x
+}
+
+// Find the centrality of each word of component c, in the order of its words.
+func findCentralityV1(word []string, adj adjacency, c Component) []Centrality {
+	central := make([]Centrality, c.words)
+	distance := make([]Index, len(word))
+	queue := make([]Index, c.words)
+	done := make([]bool, len(word))
+	histogram := make([]int, c.words)
+	for i, w := range c.word {
+		total, farthest := ssspBFS(c.word, adj, w, distance, queue, done, histogram)
+		central[i] = centrality(c, w, total, farthest, histogram)
+	}
+	return central
+}
+
+// Parallel version of findCentralityV1, with a BFS from each word in a worker.
+func findCentralityV2(word []string, adj adjacency, c Component) []Centrality {
+	// optimization -- skip parallel framework overhead for small components
+	if c.words < BREAKPOINT {
+		return findCentralityV1(word, adj, c)
+	}
+
+	central := make([]Centrality, c.words)
+	tasks := make(chan int)
+	results := make(chan bool)
+
+	// start workers
+	workers := minInt(c.words, MaxProcs)
+	for i := 0; i < workers; i++ {
+		go func(id int, in chan int, out chan bool) {
+			distance := make(Indexes, len(word))
+			queue := make(Indexes, len(c.word))
+			done := make([]bool, len(word))
+			histogram := make([]int, c.words)
+			for i := range in {
+				w := c.word[i]
+				total, farthest := ssspBFS(c.word, adj, w, distance, queue, done, histogram)
+				central[i] = centrality(c, w, total, farthest, histogram)
+				out <- true
+			}
+		}(i, tasks, results)
+	}
+
+	// start dispatcher
+	go func(out chan int) {
+		for i := range c.word {
+			out <- i
+		}
+		close(out)
+	}(tasks)
+
+	// harvest results from workers
+	for _ = range c.word {
+		<-results
+	}
+	close(results)
+	return central
+}
@@ -0,0 +1,49 @@
+package ladder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCentrality(t *testing.T) {
+	// star: the hub is adjacent to every leaf, and the leaves are two apart
+	const n = 25
+	star := namedGraph(func() ([]string, []Indexes, []Component) { return buildStarGraph(n) })
+	central := star.Centrality(0)
+	if central[0].Word != n-1 || central[0].Closeness != 1 || central[0].Harmonic != 1 {
+		t.Errorf("star: expected hub %d first with centralities 1, found %+v", n-1, central[0])
+	}
+	leaf := Centrality{0, float64(n-1) / float64(1+2*(n-2)), (1 + float64(n-2)/2) / float64(n-1)}
+	if !closeTo(central[1].Closeness, leaf.Closeness) || !closeTo(central[1].Harmonic, leaf.Harmonic) || central[1].Word != 0 {
+		t.Errorf("star: expected leaf %+v second, found %+v", leaf, central[1])
+	}
+
+	// path: the middle word is most central, then its neighbors, ending at the ends
+	path := namedGraph(func() ([]string, []Indexes, []Component) { return buildPathGraph(21) })
+	central = path.Centrality(0)
+	if central[0].Word != 10 || central[1].Word != 9 || central[2].Word != 11 || central[20].Word != 20 {
+		t.Errorf("path: expected words 10, 9, 11, ... 20, found %+v", central)
+	}
+	for i := 1; i < len(central); i++ {
+		if central[i].Closeness > central[i-1].Closeness {
+			t.Errorf("path: closeness not descending at %d: %+v", i, central)
+		}
+	}
+
+	// cycle: every word is alike
+	cycle := namedGraph(func() ([]string, []Indexes, []Component) { return buildCycleGraph(31) })
+	for i, x := range cycle.Centrality(0) {
+		if x.Word != Index(i) || !closeTo(x.Closeness, 30.0/(15*16)) || !closeTo(x.Harmonic, 2*harmonic(15)/30) {
+			t.Errorf("cycle: unexpected centrality %+v", x)
+		}
+	}
+
+	for _, test := range weightedGraphs {
+		node, a, component := test.build()
+		v1 := findCentralityV1(node, newAdjacency(a), component[0])
+		v2 := findCentralityV2(node, newAdjacency(a), component[0])
+		if !reflect.DeepEqual(v1, v2) {
+			t.Errorf("%s: centralities V1 and V2 differ", test.name)
+		}
+	}
+}
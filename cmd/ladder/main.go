@@ -0,0 +1,707 @@
+package main
+
+/*
+ * main.go -- count shortest paths among words in Doublet puzzle
+ */
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/MichaelTJones/ladder"
+)
+
+// flag processor global variables
+var wordsize, minfreq, top, verbose int
+var timing, indel, anagram, fold, keepcase, apostrophes, letters bool
+var form, cutset, hyphens string
+var input, output, freqfile, costfile, matrix, graphfile, histogram, format string
+var componentNumber int
+var costs *ladder.Costs
+var from, to string
+var counting, listing, extent, indices bool
+var limit, central, bottlenecks int
+
+func init() {
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
+
+	flag.IntVar(&wordsize, "n", 0, "number of letters (zero means any)")
+	flag.BoolVar(&timing, "t", false, "time and report progress")
+	flag.BoolVar(&indel, "indel", false, "also allow inserting or deleting one letter as a step")
+	flag.BoolVar(&anagram, "anagram", false, "also allow rearranging all letters as a step")
+	flag.IntVar(&verbose, "v", 0, "verbosity level")
+	flag.StringVar(&input, "input", "words", "input format: words, or a graph file of edges, dimacs, mtx (Matrix Market), or graph (by extension)")
+	flag.StringVar(&output, "o", "", "output wordset to file")
+	flag.StringVar(&form, "form", "none", "Unicode normalization of words: none, NFC, NFD, NFKC, or NFKD")
+	flag.BoolVar(&fold, "fold", false, "remove diacritics from words (café becomes cafe)")
+	flag.BoolVar(&keepcase, "keepcase", false, "keep upper case letters rather than lowering them")
+	flag.BoolVar(&apostrophes, "apostrophes", false, "keep apostrophes within words rather than removing them")
+	flag.BoolVar(&letters, "letters", false, "separate words at every rune but Unicode letters and marks rather than at the cutset")
+	flag.StringVar(&cutset, "cutset", "", "runes separating words (default white space, digits, and punctuation but apostrophes)")
+	flag.StringVar(&hyphens, "hyphens", "split", "hyphenated words are split in two, joined as one, or kept with the hyphen: split, join, or keep")
+	flag.IntVar(&minfreq, "minfreq", 0, "keep only words occurring at least this many times in the input")
+	flag.IntVar(&top, "top", 0, "keep only this many of the most frequent words (zero means all)")
+	flag.StringVar(&freqfile, "freq", "", "output the frequency table of the kept words to file, most frequent first")
+	flag.StringVar(&matrix, "matrix", "", "write each component's distance matrix to file")
+	flag.StringVar(&graphfile, "graph", "", "write the word graph to file as DOT (.dot, .gv), GraphML (.graphml), or a tab-separated edge list")
+	flag.IntVar(&componentNumber, "component", -1, "write only this component (numbered largest first) with -graph and -matrix")
+	flag.StringVar(&costfile, "costs", "", "file of letter costs for finding cheapest rather than shortest ladders (not with -count or -all)")
+	flag.StringVar(&from, "from", "", "first word of a Doublet to solve")
+	flag.StringVar(&to, "to", "", "last word of a Doublet to solve")
+	flag.BoolVar(&counting, "count", false, "count all shortest ladders (between every pair unless -from and -to are given)")
+	flag.BoolVar(&listing, "all", false, "print all shortest ladders from -from to -to")
+	flag.StringVar(&histogram, "histogram", "", "print the number of word pairs at each distance as a table, csv, or json")
+	flag.StringVar(&format, "format", "text", "output format: text, or json for one document describing the whole run")
+	flag.BoolVar(&indices, "indices", false, "report Wiener, Harary, degree distance, average path length, and efficiency of each component")
+	flag.IntVar(&central, "central", 0, "print the most central words of each component by closeness and harmonic centrality, this many (negative for all)")
+	flag.IntVar(&bottlenecks, "bottlenecks", 0, "print the words of each component on the most shortest ladders by betweenness centrality, this many (negative for all)")
+	flag.BoolVar(&extent, "extent", false, "report diameter, radius, center, periphery, and hardest pair of each component")
+	flag.IntVar(&limit, "limit", 1000, "maximum number of ladders printed by -all (zero means no limit)")
+}
+
+func main() {
+	start := time.Now()
+	meter := NewMeter()
+
+	if verbose >= 1 {
+		log.Printf("execution begins")
+	}
+
+	flag.Parse()
+	switch histogram {
+	case "", "table", "csv", "json":
+	default:
+		log.Fatalf("error: unknown histogram format %q (want table, csv, or json)", histogram)
+	}
+	switch input {
+	case "words", "edges", "dimacs", "mtx", "graph":
+	default:
+		log.Fatalf("error: unknown input format %q (want words, edges, dimacs, mtx, or graph)", input)
+	}
+	normalizer := ladder.Normalizer{Fold: fold, KeepCase: keepcase, KeepApostrophes: apostrophes, Letters: letters, Cutset: cutset}
+	var err error
+	if normalizer.Form, err = ladder.ParseForm(form); err != nil {
+		log.Fatalf("error: %v", err)
+	}
+	if normalizer.Hyphens, err = ladder.ParseHyphens(hyphens); err != nil {
+		log.Fatalf("error: %v", err)
+	}
+	if input != "words" && (minfreq != 0 || top != 0 || freqfile != "") {
+		log.Fatal("error: -minfreq, -top, and -freq count words, so need -input words")
+	}
+	if input != "words" && (wordsize != 0 || indel || anagram) {
+		log.Fatal("error: -n, -indel, and -anagram link words, so need -input words (a graph file gives its edges)")
+	}
+	if listing && (from == "" || to == "") {
+		log.Fatal("error: -all lists the ladders of one Doublet, so needs both -from and -to")
+	}
+	if costfile != "" && (counting || listing) {
+		log.Fatal("error: -costs finds one cheapest ladder, so cannot be used with -count or -all, which find every shortest one")
+	}
+	switch format {
+	case "text":
+	case "json":
+		if from != "" || to != "" || extent || central != 0 || bottlenecks != 0 {
+			log.Fatal("error: -format json reports on every word pair, not -from, -to, -extent, -central, or -bottlenecks")
+		}
+	default:
+		log.Fatalf("error: unknown output format %q (want text or json)", format)
+	}
+	opt := &ladder.Options{Length: wordsize, MinFrequency: minfreq, Top: top, Indel: indel, Anagram: anagram, Verbose: verbose, Normalizer: normalizer}
+
+	// Read words from files named on the command line, or if none is given,
+	// from "/usr/share/dict/words". Each word will be a node in our graph.
+	filenames := flag.Args()
+	if len(filenames) == 0 { // set default file name
+		filenames = []string{"/usr/share/dict/words"}
+	}
+	r := &Report{Files: filenames, Options: ReportOptions{wordsize, minfreq, top, indel, anagram, costfile}}
+
+	if costfile != "" {
+		var err error
+		if costs, err = ladder.LoadCosts(costfile); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	}
+
+	// Read a graph file instead when the input is not a word list. Its nodes
+	// are named by the words of the graph and its edges are given.
+	var g *ladder.Graph
+	var word []string
+	if input != "words" {
+		if len(flag.Args()) != 1 {
+			log.Fatal("error: one graph file is needed with -input")
+		}
+		kind := input
+		if kind == "graph" {
+			kind = "" // by the file's extension
+		}
+		var err error
+		if g, err = ladder.LoadGraph(filenames[0], kind, opt); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		word = g.Words()
+		r.Words = len(word)
+		meter.SetWork(float64(g.Edges())) // edges/sec
+		meter.Lap("read graph")
+		if timing {
+			log.Printf("%v read %v nodes and %v edges", meter, len(word), g.Edges())
+		}
+	} else {
+		var err error
+		if freqfile == "" {
+			word, err = ladder.ReadWords(filenames, opt)
+		} else {
+			word, err = readCounts(filenames, opt, freqfile)
+		}
+		if err != nil && word == nil {
+			log.Fatalf("error: %v", err)
+		}
+		if err != nil { // go on with the words of the files that could be read in full
+			log.Printf("warning: skipping unreadable files: %v", err)
+		}
+		r.Words = len(word)
+		meter.SetWork(float64(len(word))) // unique words/sec
+		meter.Lap("read words")
+		if timing {
+			log.Printf("%v read %v words", meter, len(word))
+		}
+	}
+
+	if output != "" {
+		if err := writeWords(word, output); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		meter.SetWork(0)
+		meter.Lap("write words")
+		if timing {
+			log.Printf("%v wrote %v words", meter, len(word))
+		}
+	}
+
+	// Determine which word-to-word transformations are allowed by the rules
+	// of Lewis Carroll's Doublets puzzle. These are the graph's edges.
+	if g == nil {
+		var err error
+		if g, err = ladder.NewGraph(word, opt); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		meter.SetWork(float64(g.Edges())) // pairs/sec
+		meter.Lap("find pairs")
+		if timing {
+			log.Printf("%v find %v pairs", meter, g.Edges())
+		}
+	}
+	r.Edges, r.Density = g.Edges(), g.Density()
+
+	// Determine graph's connected components. Each component is disconnected
+	// from the others so searching and counting are independent sub-problems.
+	component := g.Components()
+	r.Components = componentSizes(component)
+	meter.SetWork(float64(len(component))) // connected components/sec
+	meter.Lap("find components")
+	if timing {
+		log.Printf("%v find %v components", meter, len(component))
+	}
+
+	if componentNumber >= len(component) {
+		log.Fatalf("error: -component %d is out of range (there are %d components)", componentNumber, len(component))
+	}
+
+	// Write the graph for visualization and other graph tools.
+	if graphfile != "" {
+		if err := writeGraph(g, graphfile); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		meter.SetWork(0)
+		meter.Lap("write graph")
+		if timing {
+			log.Printf("%v wrote graph", meter)
+		}
+	}
+
+	// Write the distance matrix of every component with more than one word so that
+	// offline analysis need not repeat the breadth first search from every word.
+	if matrix != "" {
+		n, err := writeDistances(g, matrix)
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		meter.SetWork(float64(n)) // matrices/sec
+		meter.Lap("write distance matrices")
+		if timing {
+			log.Printf("%v wrote %v distance matrices", meter, n)
+		}
+	}
+
+	// Solve a single Doublet when the first and last words are given, rather
+	// than summing the shortest paths between every pair of words.
+	if from != "" || to != "" {
+		if from == "" || to == "" {
+			log.Fatal("error: both -from and -to words are needed to solve a Doublet")
+		}
+		solve(g, from, to)
+		meter.SetWork(0)
+		meter.Lap("solve")
+		if timing {
+			log.Printf("%v solve %v to %v", meter, from, to)
+		}
+		return
+	}
+
+	// report the extent of each component, whose hardest pairs are the longest Doublets
+	if extent {
+		n := printExtents(g)
+		meter.SetWork(float64(n)) // components/sec
+		meter.Lap("find extents")
+		if timing {
+			log.Printf("%v find extent of %v components", meter, n)
+		}
+	}
+
+	// report the most central words of each component, the best hubs for puzzles
+	if central != 0 {
+		n := printCentrality(g, central)
+		meter.SetWork(float64(n)) // words/sec
+		meter.Lap("find centrality")
+		if timing {
+			log.Printf("%v find centrality of %v words", meter, n)
+		}
+	}
+
+	// report the words most shortest ladders pass through, the bottlenecks of each component
+	if bottlenecks != 0 {
+		n := printBottlenecks(g, bottlenecks)
+		meter.SetWork(float64(n)) // words/sec
+		meter.Lap("find betweenness")
+		if timing {
+			log.Printf("%v find betweenness of %v words", meter, n)
+		}
+	}
+
+	// report the distance-based topological indices of the graph and its components
+	if indices {
+		total, index := g.Indices()
+		r.Indices = &total
+		meter.SetWork(float64(total.Pairs)) // paths/sec
+		meter.Lap("find indices")
+		if timing {
+			log.Printf("%v find indices of %v components", meter, len(index))
+		}
+		if format == "text" {
+			printIndices(total, index)
+		}
+	}
+
+	// count one cheapest path between each word pair in each component
+	var count, total int
+	var lengths *big.Int // with -count, the summed lengths of every shortest path
+	if costs != nil {
+		count, total = g.SumCheapestPaths(costs)
+		r.Pairs, r.Costs = count, &total
+		meter.SetWork(float64(count)) // paths/sec
+		meter.Lap("find cheapest paths")
+		if timing {
+			log.Printf("%v find %v paths", meter, count)
+		}
+		if format == "text" {
+			fmt.Printf("%12d word pairs\n", count)
+			fmt.Printf("%12d summed costs of one cheapest path per pair\n", total)
+		}
+	}
+
+	// count one shortest length path between each word pair in each component,
+	// and when asked, how many of those paths have each length
+	if costs == nil && !counting {
+		if histogram != "" {
+			count, total, r.Histogram = g.DistanceHistogram()
+		} else {
+			count, _, total = g.SumShortestPaths()
+		}
+		r.Pairs, r.Lengths = count, big.NewInt(int64(total))
+		meter.SetWork(float64(count)) // paths/sec
+		meter.Lap("find shortest paths")
+		if timing {
+			log.Printf("%v find %v paths", meter, count)
+		}
+		if format == "text" {
+			fmt.Printf("%12d word pairs\n", count)
+			fmt.Printf("%12d summed lengths of one shortest path per pair\n", total)
+		}
+	} else if histogram != "" {
+		_, _, r.Histogram = g.DistanceHistogram()
+		meter.SetWork(0)
+		meter.Lap("find distance histogram")
+		if timing {
+			log.Printf("%v find distance histogram", meter)
+		}
+	}
+	if histogram != "" && format == "text" {
+		pairs, lengths := 0, 0
+		for d, n := range r.Histogram {
+			pairs += n
+			lengths += d * n
+		}
+		if err := printHistogram(histogram, pairs, lengths, r.Histogram); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	}
+
+	// count every shortest length path between each word pair in each component
+	if counting {
+		var paths *big.Int
+		count, paths, lengths = g.SumAllShortestPaths()
+		r.Pairs, r.Paths, r.Lengths = count, paths, lengths
+		work, _ := new(big.Float).SetInt(paths).Float64()
+		meter.SetWork(work) // paths/sec
+		meter.Lap("find all shortest paths")
+		if timing {
+			log.Printf("%v find %v paths", meter, paths)
+		}
+		if format == "text" {
+			fmt.Printf("%12d word pairs\n", count)
+			fmt.Printf("%12d shortest paths\n", paths)
+			fmt.Printf("%12d summed lengths of all shortest paths\n", lengths)
+		}
+	}
+
+	elapsed := float64(time.Now().Sub(start)) / 1e9
+	if verbose >= 1 {
+		log.Printf("execution ends, elapsed time = %.6f seconds", elapsed)
+	}
+	if format == "json" {
+		r.Phases, r.Elapsed = meter.Phases(), elapsed
+		if err := r.Write(os.Stdout); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	} else if timing {
+		if lengths == nil {
+			lengths = big.NewInt(int64(total))
+		}
+		fmt.Printf("# %12.6f %12d %12d %2d %6d %v\n", elapsed, count, lengths, wordsize, len(word), filenames)
+	}
+}
+
+// Solve the Doublet of changing the first word into the last and print one shortest
+// (or with -costs, cheapest) ladder between them, or explain why there is none.
+func solve(g *ladder.Graph, first, last string) {
+	if costs != nil {
+		l, cost, err := g.CheapestLadder(first, last, costs)
+		if err != nil {
+			fmt.Printf("no ladder: %v\n", err)
+			return
+		}
+		printLadder(g, l)
+		fmt.Printf("%12d total cost\n", cost)
+		return
+	}
+
+	if !counting && !listing {
+		l, err := g.Ladder(first, last)
+		if err != nil {
+			fmt.Printf("no ladder: %v\n", err)
+			return
+		}
+		if verbose >= 1 {
+			log.Printf("found %d-step ladder from %s to %s\n", len(l)-1, l[0], l[len(l)-1])
+		}
+		printLadder(g, l)
+		return
+	}
+
+	// all shortest ladders: list them, count them, or both
+	var ladders [][]string
+	if listing {
+		var err error
+		ladders, err = g.Ladders(first, last, limit)
+		if err != nil {
+			fmt.Printf("no ladder: %v\n", err)
+			return
+		}
+		for _, l := range ladders {
+			printLadder(g, l)
+		}
+	}
+	if counting || (limit > 0 && len(ladders) >= limit) {
+		n, err := g.CountLadders(first, last)
+		if err != nil {
+			fmt.Printf("no ladder: %v\n", err)
+			return
+		}
+		if listing && n.Cmp(big.NewInt(int64(len(ladders)))) > 0 {
+			fmt.Printf("(stopped after listing %d of %v shortest ladders)\n", len(ladders), n)
+		}
+		if counting {
+			fmt.Printf("%12v shortest ladders from %s to %s\n", n, first, last)
+		}
+	}
+}
+
+// print the extent of each component having more than two words (in smaller ones
+// every word is both center and periphery), returning the number of components
+func printExtents(g *ladder.Graph) int {
+	n := 0
+	for cn, c := range g.Components() {
+		if c.Len() <= 2 {
+			break // components are sorted largest first
+		}
+		e := g.Extent(cn)
+		fmt.Printf("component %d: %d words, diameter %d, radius %d\n", cn, c.Len(), e.Diameter, e.Radius)
+		fmt.Printf("%12d center words: %s\n", len(e.Center), strings.Join(e.Center, " "))
+		fmt.Printf("%12d periphery words: %s\n", len(e.Periphery), strings.Join(e.Periphery, " "))
+		fmt.Printf("%12s hardest pair: ", "")
+		printLadder(g, e.Hardest)
+		n++
+	}
+	return n
+}
+
+// print the number of word pairs at each distance as a table, csv, or json
+func printHistogram(format string, pairs, lengths int, histogram []int) error {
+	switch format {
+	case "table":
+		fmt.Printf("%12s %12s %9s %9s\n", "distance", "pairs", "percent", "cumulative")
+		cumulative := 0
+		for d := 1; d < len(histogram); d++ {
+			cumulative += histogram[d]
+			fmt.Printf("%12d %12d %8.3f%% %8.3f%%\n", d, histogram[d],
+				100*float64(histogram[d])/float64(pairs), 100*float64(cumulative)/float64(pairs))
+		}
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write([]string{"distance", "pairs"})
+		for d := 1; d < len(histogram); d++ {
+			w.Write([]string{strconv.Itoa(d), strconv.Itoa(histogram[d])})
+		}
+		w.Flush()
+		return w.Error()
+	case "json":
+		if len(histogram) == 0 {
+			histogram = []int{0} // distance zero, never a pair, is always present
+		}
+		b, err := json.MarshalIndent(struct {
+			Pairs     int   `json:"pairs"`
+			Lengths   int   `json:"lengths"`
+			Histogram []int `json:"histogram"` // pairs at each distance, from zero
+		}{pairs, lengths, histogram}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	default:
+		return fmt.Errorf("unknown histogram format %q (want table, csv, or json)", format)
+	}
+	return nil
+}
+
+// print the k most central words (or all when k is negative) of each component having
+// more than two words, returning the number of words whose centrality was found
+func printCentrality(g *ladder.Graph, k int) int {
+	n := 0
+	for cn, c := range g.Components() {
+		if c.Len() <= 2 {
+			break // components are sorted largest first
+		}
+		list := g.Centrality(cn)
+		n += len(list)
+		if k >= 0 && k < len(list) {
+			list = list[:k]
+		}
+		width := len("word")
+		for _, x := range list {
+			if n := utf8.RuneCountInString(g.Word(x.Word)); n > width {
+				width = n
+			}
+		}
+		fmt.Printf("component %d: %d words, most central first\n", cn, c.Len())
+		fmt.Printf("%12s %-*s %10s %10s\n", "rank", width, "word", "closeness", "harmonic")
+		for i, x := range list {
+			fmt.Printf("%12d %-*s %10.6f %10.6f\n", i+1, width, g.Word(x.Word), x.Closeness, x.Harmonic)
+		}
+	}
+	return n
+}
+
+// print the k words of greatest betweenness (or all when k is negative) of each
+// component having more than two words, returning the number of words whose
+// betweenness was found
+func printBottlenecks(g *ladder.Graph, k int) int {
+	n := 0
+	for cn, c := range g.Components() {
+		if c.Len() <= 2 {
+			break // components are sorted largest first
+		}
+		list := g.Bottlenecks(cn, k)
+		n += c.Len()
+		width := len("word")
+		for _, x := range list {
+			if n := utf8.RuneCountInString(g.Word(x.Word)); n > width {
+				width = n
+			}
+		}
+		fmt.Printf("component %d: %d words, greatest betweenness first\n", cn, c.Len())
+		fmt.Printf("%12s %-*s %16s\n", "rank", width, "word", "betweenness")
+		for i, x := range list {
+			fmt.Printf("%12d %-*s %16.3f\n", i+1, width, g.Word(x.Word), x.Betweenness)
+		}
+	}
+	return n
+}
+
+// print the topological indices of components having more than two words, and of
+// the whole graph
+func printIndices(total ladder.Indices, index []ladder.Indices) {
+	fmt.Printf("%9s %8s %12s %14s %16s %16s %12s %10s\n",
+		"component", "words", "pairs", "wiener", "harary", "degree-distance", "average-path", "efficiency")
+	line := func(name string, x ladder.Indices) {
+		fmt.Printf("%9s %8d %12d %14d %16.3f %16d %12.6f %10.6f\n",
+			name, x.Words, x.Pairs, x.Wiener, x.Harary, x.DegreeDistance, x.AveragePathLength, x.Efficiency)
+	}
+	for cn, x := range index {
+		if x.Words <= 2 {
+			break // components are sorted largest first
+		}
+		line(strconv.Itoa(cn), x)
+	}
+	line("total", total)
+}
+
+// print a ladder, describing each step when there are several rules or when verbose
+func printLadder(g *ladder.Graph, l []string) {
+	if !indel && !anagram && verbose < 1 {
+		fmt.Println(strings.Join(l, " -> "))
+		return
+	}
+	step, err := g.Steps(l)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+	fmt.Printf("%s", l[0])
+	for _, s := range step {
+		fmt.Printf(" -> %s (%v)", s.To, s)
+	}
+	fmt.Println()
+}
+
+// write the distance matrices of components having more than one word (or with
+// -component, of that one component) to a file
+func writeDistances(g *ladder.Graph, filename string) (int, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	n := 0
+	for cn, c := range g.Components() {
+		if c.Len() < 2 && componentNumber < 0 {
+			break // components are sorted largest first
+		}
+		if componentNumber >= 0 && cn != componentNumber {
+			continue
+		}
+		if err := g.WriteDistances(w, cn); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := w.Flush(); err != nil {
+		return n, err
+	}
+
+	if verbose >= 1 {
+		log.Printf("wrote %v distance matrices to file %v", n, filename)
+	}
+	return n, file.Close()
+}
+
+// write the word graph, or with -component one component, in the format named by
+// the file's extension
+func writeGraph(g *ladder.Graph, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".dot", ".gv":
+		err = g.WriteDOT(file, componentNumber)
+	case ".graphml":
+		err = g.WriteGraphML(file, componentNumber)
+	default:
+		err = g.WriteEdgeList(file, componentNumber)
+	}
+	if err != nil {
+		return err
+	}
+
+	if verbose >= 1 {
+		log.Printf("wrote graph to file %v", filename)
+	}
+	return file.Close()
+}
+
+// read words with their counts, write the frequency table to filename, and return
+// the words in alphabetical order, along with any error reading the files
+func readCounts(filenames []string, opt *ladder.Options, filename string) ([]string, error) {
+	count, readErr := ladder.CountWords(filenames, opt)
+	if count == nil {
+		return nil, readErr
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	word := make([]string, len(count))
+	for i, c := range count {
+		fmt.Fprintf(w, "%d\t%s\n", c.Count, c.Word)
+		word[i] = c.Word
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	if verbose >= 1 {
+		log.Printf("wrote frequencies of %v words to file %v", len(word), filename)
+	}
+	sort.Strings(word)
+	return word, readErr
+}
+
+func writeWords(word []string, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	for _, s := range word {
+		w.WriteString(s)
+		w.WriteString("\n")
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if verbose >= 1 {
+		log.Printf("wrote %v words to file %v", len(word), filename)
+	}
+	return nil
+}
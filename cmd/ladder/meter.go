@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+//
+// simple performance meter: instantiate one and print it first on output lines.
+// set work to display activities per second (bytes, pages, queries, words etc.)
+// run this program with "-t" to see it in action. It is helpful to see elapsed
+// times and also effective degree of parallelism across various parts of code.
+// call Lap at the end of each phase of work to measure and record it; printing
+// the meter shows the measurements of the latest lap.
+//
+
+type Meter struct {
+	now     time.Time
+	user    float64
+	system  float64
+	memory  uint64
+	elapsed float64
+	dUser   float64
+	dSystem float64
+	dMemory uint64
+	work    float64
+	phase   []Phase
+}
+
+// Phase is the measurement of one lap of the meter, as reported by -format json.
+type Phase struct {
+	Name      string  `json:"name"`
+	Elapsed   float64 `json:"elapsed"` // seconds
+	User      float64 `json:"user"`    // seconds
+	System    float64 `json:"system"`  // seconds
+	MemoryMiB float64 `json:"memory_mib"`
+	Work      float64 `json:"work,omitempty"` // activities done, per second is Work/Elapsed
+}
+
+func NewMeter() *Meter {
+	m := &Meter{now: time.Now()}
+	m.user, m.system, m.memory = ProcessTimes()
+	return m
+}
+
+func ProcessTimes() (user, system float64, size uint64) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		fmt.Printf("Error: unable to gather resource usage data: %v\n", err)
+	}
+	user = float64(usage.Utime.Sec) + float64(usage.Utime.Usec)/1e6
+	system = float64(usage.Stime.Sec) + float64(usage.Stime.Usec)/1e6
+	size = uint64(usage.Maxrss) // peak resident set size, in bytes on macOS...
+	if runtime.GOOS != "darwin" {
+		size *= 1024 // ...and in kilobytes elsewhere
+	}
+	return
+}
+
+func (m *Meter) SetWork(work float64) {
+	m.work = work
+}
+
+// Lap measures the phase of work done since the previous lap and records it by name.
+func (m *Meter) Lap(name string) {
+	now := time.Now()
+	user, system, memory := ProcessTimes()
+
+	m.elapsed = float64((now.Sub(m.now))) / 1e9
+	m.dUser = user - m.user
+	m.dSystem = system - m.system
+	m.dMemory = memory - m.memory
+
+	m.now = now
+	m.user = user
+	m.system = system
+	m.memory = memory
+
+	m.phase = append(m.phase, Phase{name, m.elapsed, m.dUser, m.dSystem, float64(m.dMemory) / (1024.0 * 1024.0), m.work})
+}
+
+// Phases returns the measurements of every lap, in order.
+func (m *Meter) Phases() []Phase {
+	return m.phase
+}
+
+func (m *Meter) String() string {
+	elapsed, dUser, dSystem, dMemory := m.elapsed, m.dUser, m.dSystem, m.dMemory
+
+	var s string
+	if m.work > 0 && dUser >= 0.0001 {
+		s = fmt.Sprintf("%12.6f (%10.3f+%9.3f) %7.3f%% %9.3f MiB %9.0f/sec (%11.0f/sec)",
+			elapsed, dUser, dSystem, 100*(dUser+dSystem)/elapsed, float64(dMemory)/(1024.0*1024.0), m.work/dUser, m.work/elapsed)
+	} else {
+		s = fmt.Sprintf("%12.6f (%10.3f+%9.3f) %7.3f%% %9.3f MiB                                ",
+			elapsed, dUser, dSystem, 100*(dUser+dSystem)/elapsed, float64(dMemory)/(1024.0*1024.0))
+	}
+	return s
+}
@@ -0,0 +1,71 @@
+package main
+
+/*
+ * report.go -- the results of a run as one JSON document
+ */
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+
+	"github.com/MichaelTJones/ladder"
+)
+
+// Report is the structured result of a run, printed by -format json so that
+// results can be archived and compared without scraping the text output.
+type Report struct {
+	Files      []string        `json:"files"`
+	Options    ReportOptions   `json:"options"`
+	Words      int             `json:"words"`
+	Edges      int             `json:"edges"`
+	Density    float64         `json:"density"`    // fraction of possible edges present
+	Components []SizeCount     `json:"components"` // number of components of each size, largest first
+	Pairs      int             `json:"pairs"`
+	Paths      *big.Int        `json:"paths,omitempty"`     // with -count, every shortest path
+	Lengths    *big.Int        `json:"lengths,omitempty"`   // summed lengths of the paths
+	Costs      *int            `json:"costs,omitempty"`     // with -costs, summed costs of cheapest paths
+	Histogram  []int           `json:"histogram,omitempty"` // with -histogram, pairs at each distance from zero
+	Indices    *ladder.Indices `json:"indices,omitempty"`   // with -indices, of the whole graph
+	Phases     []Phase         `json:"phases"`
+	Elapsed    float64         `json:"elapsed"` // seconds
+}
+
+// ReportOptions are the command line options that shape the word graph.
+type ReportOptions struct {
+	Length       int    `json:"length"`
+	MinFrequency int    `json:"min_frequency,omitempty"`
+	Top          int    `json:"top,omitempty"`
+	Indel        bool   `json:"indel"`
+	Anagram      bool   `json:"anagram"`
+	Costs        string `json:"costs,omitempty"`
+}
+
+// SizeCount is the number of components having a given number of words.
+type SizeCount struct {
+	Words      int `json:"words"`
+	Components int `json:"components"`
+}
+
+// count the components of each size; they are sorted largest first
+func componentSizes(component ladder.Components) []SizeCount {
+	var size []SizeCount
+	for _, c := range component {
+		if n := len(size); n > 0 && size[n-1].Words == c.Len() {
+			size[n-1].Components++
+		} else {
+			size = append(size, SizeCount{c.Len(), 1})
+		}
+	}
+	return size
+}
+
+// Write writes the report to w as indented JSON.
+func (r *Report) Write(w io.Writer) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,46 @@
+package main
+
+/*
+ * zstd.go -- read Zstandard input through the zstd command
+ */
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/MichaelTJones/ladder"
+)
+
+// Zstandard is not in the standard library, so the command reads it through the
+// zstd command, which must be installed to read such files.
+func init() {
+	ladder.RegisterDecompressor([]byte{0x28, 0xb5, 0x2f, 0xfd}, zstdReader)
+}
+
+// the output of the zstd command, closed by waiting for the command to finish
+type zstdCommand struct {
+	io.ReadCloser
+	cmd *exec.Cmd
+}
+
+func (z *zstdCommand) Close() error {
+	io.Copy(io.Discard, z.ReadCloser) // let zstd finish writing if the reader stopped early
+	return z.cmd.Wait()
+}
+
+// decompress r with the zstd command
+func zstdReader(r io.Reader) (io.ReadCloser, error) {
+	cmd := exec.Command("zstd", "-d", "-c", "-q")
+	cmd.Stdin = r
+	cmd.Stderr = os.Stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("zstandard input needs the zstd command: %v", err)
+	}
+	return &zstdCommand{out, cmd}, nil
+}
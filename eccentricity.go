@@ -0,0 +1,111 @@
+package ladder
+
+/*
+ * eccentricity.go -- how far apart the words of each component lie
+ */
+
+// Extent describes how far apart the words of a component lie. The eccentricity
+// of a word is its distance to the farthest word of its component; the diameter
+// and radius are the greatest and least eccentricities, the center and periphery
+// are the words having them, and the hardest pair is two words a diameter apart.
+// Their ladder is as long as any Doublet in the component can be.
+type Extent struct {
+	Eccentricity []int    // eccentricity of each word, in the order of Component.Words
+	Diameter     int      // greatest eccentricity
+	Radius       int      // least eccentricity
+	Center       []string // words whose eccentricity is the radius, in order
+	Periphery    []string // words whose eccentricity is the diameter, in order
+	Hardest      []string // a shortest ladder between a pair of words a diameter apart
+}
+
+// Extent finds the eccentricity of each word of component cn (a position in the
+// list returned by Components) and the extremes of those eccentricities.
+func (g *Graph) Extent(cn int) *Extent {
+	c := g.Components()[cn]
+	e := &Extent{Eccentricity: findEccentricitiesV2(g.word, g.adj, c)}
+
+	e.Radius = INFINITY
+	for _, ecc := range e.Eccentricity {
+		e.Diameter = maxInt(e.Diameter, ecc)
+		e.Radius = minInt(e.Radius, ecc)
+	}
+	var first Index
+	for i, ecc := range e.Eccentricity {
+		w := c.word[i]
+		if ecc == e.Radius {
+			e.Center = append(e.Center, g.word[w])
+		}
+		if ecc == e.Diameter {
+			if len(e.Periphery) == 0 {
+				first = w
+			}
+			e.Periphery = append(e.Periphery, g.word[w])
+		}
+	}
+
+	// the hardest pair: the first peripheral word and the first word farthest from it
+	distance := make([]Index, len(g.word))
+	queue := make([]Index, c.words)
+	done := make([]bool, len(g.word))
+	ssspBFS(c.word, g.adj, first, distance, queue, done, nil)
+	for _, w := range c.word {
+		if int(distance[w]) == e.Diameter {
+			e.Hardest = g.words(findLadder(g.adj, c, first, w))
+			break
+		}
+	}
+	return e
+}
+
+// Find the eccentricity of each word of component c, in the order of its words.
+func findEccentricitiesV1(word []string, adj adjacency, c Component) []int {
+	eccentricity := make([]int, c.words)
+	distance := make([]Index, len(word))
+	queue := make([]Index, c.words)
+	done := make([]bool, len(word))
+	for i, w := range c.word {
+		_, eccentricity[i] = ssspBFS(c.word, adj, w, distance, queue, done, nil)
+	}
+	return eccentricity
+}
+
+// Parallel version of findEccentricitiesV1, with a BFS from each word in a worker.
+func findEccentricitiesV2(word []string, adj adjacency, c Component) []int {
+	// optimization -- skip parallel framework overhead for small components
+	if c.words < BREAKPOINT {
+		return findEccentricitiesV1(word, adj, c)
+	}
+
+	eccentricity := make([]int, c.words)
+	tasks := make(chan int)
+	results := make(chan bool)
+
+	// start workers
+	workers := minInt(c.words, MaxProcs)
+	for i := 0; i < workers; i++ {
+		go func(id int, in chan int, out chan bool) {
+			distance := make(Indexes, len(word))
+			queue := make(Indexes, len(c.word))
+			done := make([]bool, len(word))
+			for i := range in {
+				_, eccentricity[i] = ssspBFS(c.word, adj, c.word[i], distance, queue, done, nil)
+				out <- true
+			}
+		}(i, tasks, results)
+	}
+
+	// start dispatcher
+	go func(out chan int) {
+		for i := range c.word {
+			out <- i
+		}
+		close(out)
+	}(tasks)
+
+	// harvest results from workers
+	for _ = range c.word {
+		<-results
+	}
+	close(results)
+	return eccentricity
+}
@@ -0,0 +1,45 @@
+package ladder
+
+import (
+	"reflect"
+	"testing"
+)
+
+var extentGraphs = []struct {
+	name                                string
+	build                               Builder
+	diameter, radius, center, periphery int
+}{
+	{"path-50", func() ([]string, []Indexes, []Component) { return buildPathGraph(50) }, 49, 25, 2, 2},
+	{"path-51", func() ([]string, []Indexes, []Component) { return buildPathGraph(51) }, 50, 25, 1, 2},
+	{"cycle", func() ([]string, []Indexes, []Component) { return buildCycleGraph(51) }, 25, 25, 51, 51},
+	{"complete", func() ([]string, []Indexes, []Component) { return buildCompleteGraph(10) }, 1, 1, 10, 10},
+	{"star", func() ([]string, []Indexes, []Component) { return buildStarGraph(20) }, 2, 1, 1, 19},
+	{"wheel", func() ([]string, []Indexes, []Component) { return buildWheelGraph(40) }, 2, 1, 1, 39},
+	{"grid", func() ([]string, []Indexes, []Component) { return build2DGridGraph(9, 7) }, 14, 7, 1, 4},
+	{"bipartite", func() ([]string, []Indexes, []Component) { return buildCompleteBipartiteGraph(12, 5) }, 2, 2, 17, 17},
+	{"tree", func() ([]string, []Indexes, []Component) { return buildCompleteBinaryTree(5) }, 10, 5, 1, 32},
+}
+
+func TestExtent(t *testing.T) {
+	for _, test := range extentGraphs {
+		g := namedGraph(test.build)
+		e := g.Extent(0)
+		if e.Diameter != test.diameter || e.Radius != test.radius ||
+			len(e.Center) != test.center || len(e.Periphery) != test.periphery {
+			t.Errorf("%s: expected diameter %d, radius %d, %d center and %d periphery words; "+
+				"found %d, %d, %d, and %d", test.name, test.diameter, test.radius, test.center, test.periphery,
+				e.Diameter, e.Radius, len(e.Center), len(e.Periphery))
+		}
+		if len(e.Hardest) != e.Diameter+1 || e.Hardest[0] != e.Periphery[0] {
+			t.Errorf("%s: hardest ladder %v is not a diameter long from %s", test.name, e.Hardest, e.Periphery[0])
+		}
+
+		c := g.Components()[0]
+		v1 := findEccentricitiesV1(g.word, g.adj, c)
+		v2 := findEccentricitiesV2(g.word, g.adj, c)
+		if !reflect.DeepEqual(v1, v2) {
+			t.Errorf("%s: eccentricities V1 %v and V2 %v differ", test.name, v1, v2)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package ladder
+
+import (
+	"fmt"
+	"sort"
+)
+
+// An EdgeKind names the rule of the Doublets variant that links two words.
+type EdgeKind uint8
+
+const (
+	Substitute EdgeKind = iota // change one letter (Lewis Carroll's rule)
+	Insert                     // insert one letter
+	Delete                     // delete one letter
+	Anagram                    // rearrange all of the letters
+	Link                       // an edge read from a graph file, with no rule
+)
+
+var edgeKindName = [...]string{"substitute", "insert", "delete", "anagram", "link"}
+
+func (k EdgeKind) String() string {
+	if int(k) < len(edgeKindName) {
+		return edgeKindName[k]
+	}
+	return fmt.Sprintf("EdgeKind(%d)", k)
+}
+
+// An Edge tags the link from one word to another with the rule that makes it and
+// the position, counted in runes from zero, of the letter changed, inserted into
+// the second word, or deleted from the first. The kind is kept in the top four
+// bits and the position in the other 28, enough for a word of any length.
+type Edge uint32
+
+const edgePosBits = 28
+
+func newEdge(kind EdgeKind, pos int) Edge {
+	return Edge(kind)<<edgePosBits | Edge(pos)&(1<<edgePosBits-1)
+}
+
+// Kind returns the rule that makes the edge.
+func (e Edge) Kind() EdgeKind { return EdgeKind(e >> edgePosBits) }
+
+// Pos returns the position of the changed letter (zero for anagrams and links).
+func (e Edge) Pos() int { return int(e & (1<<edgePosBits - 1)) }
+
+func (e Edge) String() string {
+	if k := e.Kind(); k == Anagram || k == Link {
+		return k.String()
+	}
+	return fmt.Sprintf("%v %d", e.Kind(), e.Pos()+1)
+}
+
+// A Step is one step of a ladder, from one word to the next.
+type Step struct {
+	From, To string
+	Edge
+}
+
+// String describes the step as a puzzle solver might, such as "change 3rd letter l→r".
+func (s Step) String() string {
+	from, to := []rune(s.From), []rune(s.To)
+	pos := s.Pos()
+	switch s.Kind() {
+	case Substitute:
+		return fmt.Sprintf("change %s letter %c→%c", ordinal(pos+1), from[pos], to[pos])
+	case Insert:
+		return fmt.Sprintf("insert %c as %s letter", to[pos], ordinal(pos+1))
+	case Delete:
+		return fmt.Sprintf("delete %s letter %c", ordinal(pos+1), from[pos])
+	case Link:
+		return "link"
+	}
+	return "rearrange letters"
+}
+
+// English ordinal number: 1st, 2nd, 3rd, 4th, ..., 11th, 12th, 13th, ..., 21st
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+// a word's links and their edge tags, to be sorted together by word number
+type links struct {
+	pair Indexes
+	tag  []Edge
+}
+
+func (l links) Len() int { return len(l.pair) }
+func (l links) Swap(i, j int) {
+	l.pair[i], l.pair[j] = l.pair[j], l.pair[i]
+	l.tag[i], l.tag[j] = l.tag[j], l.tag[i]
+}
+func (l links) Less(i, j int) bool {
+	return l.pair[i] < l.pair[j] || (l.pair[i] == l.pair[j] && l.tag[i] < l.tag[j])
+}
+
+// remove repeated links from an ordered list, keeping the first (least) tag of each
+func (l links) unique() links {
+	if len(l.pair) < 2 {
+		return l
+	}
+	n := 1
+	for i := 1; i < len(l.pair); i++ {
+		if l.pair[i] != l.pair[n-1] {
+			l.pair[n] = l.pair[i]
+			l.tag[n] = l.tag[i]
+			n++
+		}
+	}
+	return links{l.pair[:n], l.tag[:n]}
+}
+
+// a list of links from one word to another, in no particular order, as found by
+// one of the workers of findPairs
+type edgeList struct {
+	from Indexes
+	to   Indexes
+	tag  []Edge
+}
+
+func (l *edgeList) add(from, to Index, tag Edge) {
+	l.from = append(l.from, from)
+	l.to = append(l.to, to)
+	l.tag = append(l.tag, tag)
+}
+
+// add the links of the words sharing a variation with the letter at pos unknown:
+// between the words by substitution and, when there are shorter ones, between them
+// and the words by insertion and deletion
+func (l *edgeList) addVariation(pos int, long, short []variant) {
+	edge := newEdge(Substitute, pos)
+	for _, v1 := range long {
+		for _, v2 := range long {
+			if v1.word != v2.word {
+				l.add(v1.word, v2.word, edge)
+			}
+		}
+	}
+	insert, remove := newEdge(Insert, pos), newEdge(Delete, pos)
+	for _, v1 := range short { // shorter words...
+		for _, v2 := range long { // ...linked to longer ones by inserting a letter
+			l.add(v1.word, v2.word, insert)
+			l.add(v2.word, v1.word, remove)
+		}
+	}
+}
+
+// add the links between words sharing a multiset of letters
+func (l *edgeList) addAnagrams(word []string) {
+	anagram := make(map[string]Indexes, len(word))
+	for wn, w := range word {
+		runes := []rune(w)
+		sort.Sort(runeSlice(runes))
+		anagram[string(runes)] = append(anagram[string(runes)], Index(wn))
+	}
+	edge := newEdge(Anagram, 0)
+	for _, list := range anagram {
+		for _, wn1 := range list {
+			for _, wn2 := range list {
+				if wn1 != wn2 {
+					l.add(wn1, wn2, edge)
+				}
+			}
+		}
+	}
+}
+
+// Step returns the step from word w1 to word w2 and whether they are linked.
+func (g *Graph) Step(w1, w2 Index) (Step, bool) {
+	p := g.Neighbors(w1)
+	i := sort.Search(len(p), func(i int) bool { return p[i] >= w2 })
+	if i == len(p) || p[i] != w2 {
+		return Step{}, false
+	}
+	return Step{g.word[w1], g.word[w2], g.EdgeTags(w1)[i]}, true
+}
+
+// Steps returns the steps of a ladder of words, as returned by Ladder or Ladders.
+func (g *Graph) Steps(ladder []string) ([]Step, error) {
+	var step []Step
+	for i := 1; i < len(ladder); i++ {
+		w1, ok := g.Find(ladder[i-1])
+		if !ok {
+			return nil, fmt.Errorf("%q is %w", ladder[i-1], ErrUnknownWord)
+		}
+		w2, ok := g.Find(ladder[i])
+		if !ok {
+			return nil, fmt.Errorf("%q is %w", ladder[i], ErrUnknownWord)
+		}
+		s, ok := g.Step(w1, w2)
+		if !ok {
+			return nil, fmt.Errorf("%s and %s are not linked", g.word[w1], g.word[w2])
+		}
+		step = append(step, s)
+	}
+	return step, nil
+}
@@ -0,0 +1,106 @@
+package ladder
+
+/*
+ * export.go -- write the word graph for other graph tools
+ */
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteEdgeList writes the edges of the graph to w as tab-separated lines of the
+// first word, the second word, and the rule linking them, such as "substitute 3".
+// Each edge is written once, with the words in Index order. When cn is not
+// negative only the edges of component cn (a position in the list returned by
+// Components) are written. Words without edges do not appear.
+func (g *Graph) WriteEdgeList(w io.Writer, cn int) error {
+	b := bufio.NewWriter(w)
+	for _, w1 := range g.exportWords(cn) {
+		tag := g.EdgeTags(w1)
+		for i, w2 := range g.Neighbors(w1) {
+			if w1 < w2 {
+				fmt.Fprintf(b, "%s\t%s\t%v\n", g.word[w1], g.word[w2], tag[i])
+			}
+		}
+	}
+	return b.Flush()
+}
+
+// WriteDOT writes the graph to w in the DOT language of Graphviz, as an undirected
+// graph whose nodes are words and whose edges carry the rule linking them as their
+// "rule" attribute. When cn is not negative only component cn is written.
+func (g *Graph) WriteDOT(w io.Writer, cn int) error {
+	b := bufio.NewWriter(w)
+	word := g.exportWords(cn)
+	fmt.Fprintf(b, "graph ladder {\n")
+	for _, w1 := range word {
+		fmt.Fprintf(b, "\t%s;\n", dotQuote(g.word[w1]))
+	}
+	for _, w1 := range word {
+		tag := g.EdgeTags(w1)
+		for i, w2 := range g.Neighbors(w1) {
+			if w1 < w2 {
+				fmt.Fprintf(b, "\t%s -- %s [rule=%s];\n",
+					dotQuote(g.word[w1]), dotQuote(g.word[w2]), dotQuote(tag[i].String()))
+			}
+		}
+	}
+	fmt.Fprintf(b, "}\n")
+	return b.Flush()
+}
+
+// quote a DOT identifier
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// WriteGraphML writes the graph to w in GraphML, as an undirected graph whose nodes
+// ("n" and the word's Index) have a "word" attribute and whose edges have a "rule"
+// attribute. When cn is not negative only component cn is written.
+func (g *Graph) WriteGraphML(w io.Writer, cn int) error {
+	b := bufio.NewWriter(w)
+	word := g.exportWords(cn)
+	fmt.Fprintf(b, "%s", xml.Header)
+	fmt.Fprintf(b, "<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\">\n")
+	fmt.Fprintf(b, "  <key id=\"word\" for=\"node\" attr.name=\"word\" attr.type=\"string\"/>\n")
+	fmt.Fprintf(b, "  <key id=\"rule\" for=\"edge\" attr.name=\"rule\" attr.type=\"string\"/>\n")
+	fmt.Fprintf(b, "  <graph id=\"ladder\" edgedefault=\"undirected\">\n")
+	for _, w1 := range word {
+		fmt.Fprintf(b, "    <node id=\"n%d\"><data key=\"word\">%s</data></node>\n", w1, xmlEscape(g.word[w1]))
+	}
+	for _, w1 := range word {
+		tag := g.EdgeTags(w1)
+		for i, w2 := range g.Neighbors(w1) {
+			if w1 < w2 {
+				fmt.Fprintf(b, "    <edge source=\"n%d\" target=\"n%d\"><data key=\"rule\">%v</data></edge>\n",
+					w1, w2, tag[i])
+			}
+		}
+	}
+	fmt.Fprintf(b, "  </graph>\n")
+	fmt.Fprintf(b, "</graphml>\n")
+	return b.Flush()
+}
+
+// escape text for XML character data
+func xmlEscape(s string) string {
+	var e strings.Builder
+	xml.EscapeText(&e, []byte(s))
+	return e.String()
+}
+
+// the words to export: those of component cn, or every word when cn is negative
+func (g *Graph) exportWords(cn int) Indexes {
+	if cn >= 0 {
+		return g.Components()[cn].word
+	}
+	word := make(Indexes, len(g.word))
+	for i := range word {
+		word[i] = Index(i)
+	}
+	return word
+}
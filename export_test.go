@@ -0,0 +1,65 @@
+package ladder
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	g, err := NewGraph([]string{"cat", "cot", "dog", "dot", "emu"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := g.WriteEdgeList(&b, -1); err != nil {
+		t.Fatal(err)
+	}
+	expected := "cat\tcot\tsubstitute 2\ncot\tdot\tsubstitute 1\ndog\tdot\tsubstitute 3\n"
+	if b.String() != expected {
+		t.Errorf("edge list: expected %q, wrote %q", expected, b.String())
+	}
+
+	b.Reset()
+	if err := g.WriteDOT(&b, 1); err != nil { // just "emu"
+		t.Fatal(err)
+	}
+	expected = "graph ladder {\n\t\"emu\";\n}\n"
+	if b.String() != expected {
+		t.Errorf("DOT: expected %q, wrote %q", expected, b.String())
+	}
+	b.Reset()
+	if err := g.WriteDOT(&b, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "\t\"cot\" -- \"dot\" [rule=\"substitute 1\"];\n") || strings.Contains(b.String(), "emu") {
+		t.Errorf("DOT: missing edge or extra word in %q", b.String())
+	}
+
+	b.Reset()
+	if err := g.WriteGraphML(&b, -1); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Graph struct {
+			Node []struct {
+				ID   string `xml:"id,attr"`
+				Data string `xml:"data"`
+			} `xml:"node"`
+			Edge []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+				Data   string `xml:"data"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal(b.Bytes(), &doc); err != nil {
+		t.Fatalf("GraphML: %v", err)
+	}
+	if len(doc.Graph.Node) != 5 || len(doc.Graph.Edge) != 3 || doc.Graph.Node[4].Data != "emu" ||
+		doc.Graph.Edge[2].Source != "n2" || doc.Graph.Edge[2].Target != "n3" || doc.Graph.Edge[2].Data != "substitute 3" {
+		t.Errorf("GraphML: unexpected document %+v", doc)
+	}
+}
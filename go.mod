@@ -0,0 +1,5 @@
+module github.com/MichaelTJones/ladder
+
+go 1.25.0
+
+require golang.org/x/text v0.40.0
@@ -0,0 +1,274 @@
+package ladder
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+)
+
+// Options control how words are read and linked into a graph. The zero value
+// (or a nil *Options) selects words of any length and logs nothing.
+type Options struct {
+	Length       int  // number of letters (zero means any)
+	MinFrequency int  // fewest occurrences of a word in the files for it to be kept
+	Top          int  // keep only this many of the most frequent words (zero means all)
+	Indel        bool // also link words differing by one inserted or deleted letter
+	Anagram      bool // also link words that are anagrams of each other
+	Verbose      int  // verbosity level (1 logs progress, 2 also prints words and links)
+
+	Normalizer Normalizer // how text is split into words and each word made standard
+}
+
+// A WordCount is a word and the number of times it occurs in the files read.
+type WordCount struct {
+	Word  string
+	Count int
+}
+
+// Errors explaining why a Doublet has no ladder.
+var (
+	ErrUnknownWord  = errors.New("not in the word list")
+	ErrDisconnected = errors.New("in different components")
+)
+
+// A Graph is the word graph of a Doublet puzzle. Each word is a node, identified
+// by its Index in alphabetical order, and words that differ in a single letter
+// are linked by an edge, as are words differing by one inserted or deleted letter
+// when Options.Indel is set and anagrams when Options.Anagram is set. (A graph
+// read from a DIMACS or Matrix Market file instead keeps the numeric order of its
+// nodes.) Its methods are safe for concurrent use.
+type Graph struct {
+	word []string
+	adj  adjacency
+	tag  []Edge // tag[i] names the rule of the link adj.link[i]
+	opt  Options
+
+	numbered bool // the words are node numbers 1 to n, in numeric order
+
+	once      sync.Once
+	component Components
+
+	searchOnce sync.Once
+	search     adjacency   // adj with the words of each component numbered together
+	searched   []Component // the components in the numbers of search
+}
+
+// ReadWords reads words from the named files, which may be dictionaries or any
+// other text, and returns them as a clean, ordered list without duplicates. Words
+// occurring fewer than Options.MinFrequency times are dropped and, when Options.Top
+// is set, only that many of the most frequent are kept. When some files cannot be
+// read in full, ReadWords returns the words of the others, and none of theirs,
+// along with an error joining their errors, leaving the caller to decide whether
+// to go on without them.
+func ReadWords(name []string, opt *Options) ([]string, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	return readWords(name, opt)
+}
+
+// CountWords reads words as ReadWords does and returns them with the number of times
+// each occurs, most frequent first and then in alphabetical order, along with any
+// error reading the files as ReadWords returns.
+func CountWords(name []string, opt *Options) ([]WordCount, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	count, err := countWords(name, opt)
+	if count == nil {
+		return nil, err
+	}
+	sortByFrequency(count)
+	return count, err
+}
+
+// NewGraph builds the word graph of a list of words, which must be in
+// alphabetical order without duplicates as returned by ReadWords.
+func NewGraph(word []string, opt *Options) (*Graph, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	if len(word) < 1 {
+		return nil, errors.New("no words found")
+	}
+	if !sort.StringsAreSorted(word) {
+		return nil, errors.New("words are not in alphabetical order")
+	}
+	for i := 1; i < len(word); i++ {
+		if word[i] == word[i-1] {
+			return nil, fmt.Errorf("word %q is repeated", word[i])
+		}
+	}
+
+	g := &Graph{word: word, opt: *opt}
+	g.adj, g.tag = findPairs(word, &g.opt)
+	return g, nil
+}
+
+// ReadGraph reads words from the named files and builds their word graph. It fails
+// if any file cannot be read.
+func ReadGraph(name []string, opt *Options) (*Graph, error) {
+	word, err := ReadWords(name, opt)
+	if err != nil {
+		return nil, err
+	}
+	return NewGraph(word, opt)
+}
+
+// Len returns the number of words in the graph.
+func (g *Graph) Len() int { return len(g.word) }
+
+// Words returns the words of the graph in Index order, which is alphabetical but
+// for the numbered nodes of a DIMACS or Matrix Market file.
+func (g *Graph) Words() []string { return g.word }
+
+// Word returns the word with index w.
+func (g *Graph) Word(w Index) string { return g.word[w] }
+
+// Find returns the index of a word, normalized as the graph's words were (which
+// by default lowers its case) unless the graph has the word as given, and whether
+// it is in the graph.
+func (g *Graph) Find(s string) (Index, bool) {
+	if g.numbered {
+		w, err := nodeNumber(s, len(g.word))
+		return w, err == nil && g.word[w] == s
+	}
+	return findWord(g.word, s, &g.opt.Normalizer)
+}
+
+// Neighbors returns the indexes of the words linked to word w, in order.
+func (g *Graph) Neighbors(w Index) Indexes { return g.adj.of(w) }
+
+// EdgeTags returns the tags of the edges from word w, in the order of Neighbors.
+func (g *Graph) EdgeTags(w Index) []Edge {
+	a, b := g.adj.first[w], g.adj.first[w+1]
+	return g.tag[a:b:b]
+}
+
+// Edges returns the number of (undirected) edges between words.
+func (g *Graph) Edges() int { return len(g.adj.link) / 2 }
+
+// Density returns the fraction of the possible edges between words that are present.
+func (g *Graph) Density() float64 {
+	n := len(g.word)
+	if n < 2 {
+		return 0
+	}
+	return float64(2*g.Edges()) / float64(n*(n-1))
+}
+
+// Components returns the connected components of the graph, largest first. They
+// are found on first use.
+func (g *Graph) Components() Components {
+	g.once.Do(func() {
+		g.component = findComponents(g.word, g.adj, &g.opt)
+	})
+	return g.component
+}
+
+// ComponentOf returns the position of the component containing word w in the
+// list returned by Components.
+func (g *Graph) ComponentOf(w Index) int {
+	return componentOf(g.Components(), w)
+}
+
+// The graph with the words of each component numbered together, as relabel numbers
+// them, and its components in these numbers, for the searches from every word. They
+// are found on first use.
+func (g *Graph) searchGraph() (adjacency, []Component) {
+	g.searchOnce.Do(func() {
+		g.search, g.searched = relabel(g.adj, g.Components())
+	})
+	return g.search, g.searched
+}
+
+// SumShortestPaths sums the length of one shortest path between each ordered pair
+// of connected words, returning the number of pairs, the number of paths (one per
+// pair), and the summed lengths, which is twice the graph's Wiener index.
+func (g *Graph) SumShortestPaths() (pairs, paths, lengths int) {
+	adj, component := g.searchGraph()
+	return sumAllSourcesShortestPaths(g.word, adj, component)
+}
+
+// DistanceHistogram counts the ordered pairs of connected words at each distance.
+// It returns the number of pairs and their summed lengths, as SumShortestPaths does,
+// and the histogram, whose element d is the number of pairs d steps apart.
+func (g *Graph) DistanceHistogram() (pairs, lengths int, histogram []int) {
+	adj, component := g.searchGraph()
+	return histogramAllSourcesShortestPaths(g.word, adj, component)
+}
+
+// SumAllShortestPaths sums the lengths of every shortest path between each ordered
+// pair of connected words, returning the number of pairs, the number of distinct
+// shortest paths, and their summed lengths. The paths are counted exactly, in big
+// integers, as their number grows exponentially with the length of the ladders.
+func (g *Graph) SumAllShortestPaths() (pairs int, paths, lengths *big.Int) {
+	adj, component := g.searchGraph()
+	return sumAllSourcesAllShortestPathsV2(g.word, adj, component)
+}
+
+// Ladder returns one shortest ladder of words from first to last.
+func (g *Graph) Ladder(first, last string) ([]string, error) {
+	w1, w2, c, err := g.doublet(first, last)
+	if err != nil {
+		return nil, err
+	}
+	return g.words(findLadder(g.adj, c, w1, w2)), nil
+}
+
+// Ladders returns the distinct shortest ladders of words from first to last in
+// alphabetical order, stopping after limit ladders (zero means no limit).
+func (g *Graph) Ladders(first, last string, limit int) ([][]string, error) {
+	w1, w2, c, err := g.doublet(first, last)
+	if err != nil {
+		return nil, err
+	}
+	var ladders [][]string
+	for _, l := range listLadders(g.adj, c, w1, w2, limit) {
+		ladders = append(ladders, g.words(l))
+	}
+	return ladders, nil
+}
+
+// CountLadders returns the number of distinct shortest ladders from first to last.
+func (g *Graph) CountLadders(first, last string) (*big.Int, error) {
+	w1, w2, c, err := g.doublet(first, last)
+	if err != nil {
+		return nil, err
+	}
+	return countLadders(g.adj, c, w1, w2), nil
+}
+
+// find the first and last words of a Doublet and the component they share
+func (g *Graph) doublet(first, last string) (Index, Index, Component, error) {
+	w1, ok := g.Find(first)
+	if !ok {
+		return 0, 0, Component{}, fmt.Errorf("%q is %w", first, ErrUnknownWord)
+	}
+	w2, ok := g.Find(last)
+	if !ok {
+		return 0, 0, Component{}, fmt.Errorf("%q is %w", last, ErrUnknownWord)
+	}
+	cn := g.ComponentOf(w1)
+	if cn != g.ComponentOf(w2) {
+		return 0, 0, Component{}, fmt.Errorf("%s and %s are %w", g.word[w1], g.word[w2], ErrDisconnected)
+	}
+	return w1, w2, g.Components()[cn], nil
+}
+
+// translate word numbers into words
+func (g *Graph) words(list Indexes) []string {
+	s := make([]string, len(list))
+	for i, w := range list {
+		s[i] = g.word[w]
+	}
+	return s
+}
+
+// Words returns the indexes of the component's words, in order.
+func (c Component) Words() Indexes { return c.word }
+
+// Len returns the number of words in the component.
+func (c Component) Len() int { return c.words }
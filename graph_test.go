@@ -0,0 +1,395 @@
+package ladder
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGraphLadders(t *testing.T) {
+	g, err := ReadGraph([]string{"words/webster-4"}, &Options{Length: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ladder, err := g.Ladder("COLD", "warm")
+	if err != nil || len(ladder) != 5 || ladder[0] != "cold" || ladder[4] != "warm" {
+		t.Errorf("expected 4-step ladder from cold to warm, found %v (%v)", ladder, err)
+	}
+
+	ladders, err := g.Ladders("cold", "warm", 0)
+	if err != nil || len(ladders) != 6 {
+		t.Errorf("expected 6 ladders from cold to warm, listed %d (%v)", len(ladders), err)
+	}
+
+	count, err := g.CountLadders("cold", "warm")
+	if err != nil || count.Int64() != 6 {
+		t.Errorf("expected 6 ladders from cold to warm, counted %v (%v)", count, err)
+	}
+
+	if _, err := g.Ladder("cold", "zzzz"); !errors.Is(err, ErrUnknownWord) {
+		t.Errorf("expected %v, found %v", ErrUnknownWord, err)
+	}
+}
+
+func TestGraphComponents(t *testing.T) {
+	g, err := NewGraph([]string{"cat", "cot", "dog", "dot", "emu"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Edges() != 3 {
+		t.Errorf("expected 3 edges, found %d", g.Edges())
+	}
+	component := g.Components()
+	if len(component) != 2 || component[0].Len() != 4 || component[1].Len() != 1 {
+		t.Errorf("expected components of 4 and 1 words, found %v", component)
+	}
+	if _, err := g.Ladder("cat", "emu"); !errors.Is(err, ErrDisconnected) {
+		t.Errorf("expected %v, found %v", ErrDisconnected, err)
+	}
+	if _, err := NewGraph([]string{"dog", "cat"}, nil); err == nil {
+		t.Errorf("expected error for unordered words")
+	}
+}
+
+func TestGraphHistogram(t *testing.T) {
+	g, err := ReadGraph([]string{"words/webster-3"}, &Options{Length: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairs, _, lengths := g.SumShortestPaths()
+	hPairs, hLengths, histogram := g.DistanceHistogram()
+	if hPairs != pairs || hLengths != lengths {
+		t.Errorf("expected %d pairs summing to %d, histogram found %d and %d", pairs, lengths, hPairs, hLengths)
+	}
+	var n, sum int
+	for d, count := range histogram {
+		n += count
+		sum += d * count
+	}
+	if n != pairs || sum != lengths || histogram[len(histogram)-1] == 0 {
+		t.Errorf("histogram %v counts %d pairs summing to %d", histogram, n, sum)
+	}
+
+	adj, component := g.searchGraph()
+	_, _, v1 := histogramAllSourcesShortestPathsV1(g.word, adj, component)
+	if !reflect.DeepEqual(v1, histogram) {
+		t.Errorf("histograms V1 %v and V2 %v differ", v1, histogram)
+	}
+}
+
+// Searching the larger components from many sources at once gives the same sums and
+// histogram as searching every component from one source at a time.
+func TestGraphMultiSource(t *testing.T) {
+	words, err := ReadGraph([]string{"words/webster-2", "words/webster-3"}, &Options{Indel: true, Anagram: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the test graphs side by side, as components of 17 to 63 nodes
+	var name []string
+	var edge [][2]Index
+	for _, test := range weightedGraphs {
+		node, a, _ := test.build()
+		base := Index(len(name))
+		for w := range node {
+			name = append(name, fmt.Sprintf("%s %d", test.name, w))
+			for _, wn := range a[w] {
+				edge = append(edge, [2]Index{base + Index(w), base + wn})
+			}
+		}
+	}
+	mixed, err := newLinkedGraph(name, edge)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		pairs, paths, lengths int
+		hPairs, hLengths      int
+		histogram             []int
+	}
+	defer func(words int) { msBreakpoint = words }(msBreakpoint)
+	for _, g := range []*Graph{words, mixed} {
+		var want result
+		for i, words := range []int{g.Len() + 1, 64, 45, 1} { // none of the components, the larger, all of them
+			var r result
+			msBreakpoint = words
+			r.pairs, r.paths, r.lengths = g.SumShortestPaths()
+			r.hPairs, r.hLengths, r.histogram = g.DistanceHistogram()
+			if i == 0 {
+				want = r
+			} else if !reflect.DeepEqual(r, want) {
+				t.Errorf("%d words, from %d: expected %+v, found %+v", g.Len(), words, want, r)
+			}
+		}
+	}
+}
+
+func TestGraphIndel(t *testing.T) {
+	word := []string{"cod", "cods", "col", "cold", "colds", "cool"}
+	g, err := NewGraph(word, &Options{Indel: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// cod-cods cod-col cod-cold col-cold col-cool cods-colds cold-colds
+	if g.Edges() != 7 {
+		t.Errorf("expected 7 edges, found %d", g.Edges())
+	}
+	for w := range word {
+		n := g.Neighbors(Index(w))
+		for i := 1; i < len(n); i++ {
+			if n[i] <= n[i-1] {
+				t.Errorf("%s: neighbors out of order or repeated: %v", word[w], n)
+			}
+		}
+	}
+
+	count, err := g.CountLadders("cold", "cods")
+	if err != nil || count.Int64() != 2 {
+		t.Errorf("expected 2 ladders from cold to cods, counted %v (%v)", count, err)
+	}
+
+	g, err = NewGraph(word, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Edges() != 1 { // cod-col
+		t.Errorf("expected 1 edge without insertion and deletion, found %d", g.Edges())
+	}
+}
+
+func TestGraphAnagram(t *testing.T) {
+	word := []string{"evil", "lice", "live", "veil", "vile"}
+	g, err := NewGraph(word, &Options{Anagram: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// lice-live by substitution, and the 6 pairs among evil, live, veil, vile
+	if g.Edges() != 7 {
+		t.Errorf("expected 7 edges, found %d", g.Edges())
+	}
+	l, err := g.Steps([]string{"lice", "live", "evil"})
+	if err != nil || len(l) != 2 || l[0].Kind() != Substitute || l[1].Kind() != Anagram {
+		t.Errorf("expected %v then %v from lice to evil, found %v (%v)", Substitute, Anagram, l, err)
+	}
+}
+
+func TestGraphSteps(t *testing.T) {
+	word := []string{"cod", "cods", "col", "cold", "colds", "cool", "cord"}
+	g, err := NewGraph(word, &Options{Indel: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		from, to string
+		kind     EdgeKind
+		pos      int
+		say      string
+	}{
+		{"cold", "cord", Substitute, 2, "change 3rd letter l→r"},
+		{"cod", "cold", Insert, 2, "insert l as 3rd letter"},
+		{"colds", "cods", Delete, 2, "delete 3rd letter l"},
+		{"col", "cool", Insert, 1, "insert o as 2nd letter"}, // first of two gaps
+		{"cool", "col", Delete, 1, "delete 2nd letter o"},
+	} {
+		step, err := g.Steps([]string{test.from, test.to})
+		if err != nil {
+			t.Errorf("%s -> %s: %v", test.from, test.to, err)
+			continue
+		}
+		s := step[0]
+		if s.Kind() != test.kind || s.Pos() != test.pos || s.String() != test.say {
+			t.Errorf("%s -> %s: expected (%v, %d, %q), found (%v, %d, %q)",
+				test.from, test.to, test.kind, test.pos, test.say, s.Kind(), s.Pos(), s.String())
+		}
+	}
+
+	if _, err := g.Steps([]string{"cod", "cool"}); err == nil {
+		t.Errorf("expected error for unlinked words")
+	}
+}
+
+func TestGraphWordCounts(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "text")
+	text := "The cat sat on the mat. The cat ran; a dog sat. Teh end.\n"
+	if err := os.WriteFile(name, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := CountWords([]string{name}, &Options{Length: 3})
+	expected := []WordCount{{"the", 3}, {"cat", 2}, {"sat", 2}, {"dog", 1}, {"end", 1}, {"mat", 1}, {"ran", 1}, {"teh", 1}}
+	if err != nil || !reflect.DeepEqual(count, expected) {
+		t.Errorf("expected %v, counted %v (%v)", expected, count, err)
+	}
+
+	word, err := ReadWords([]string{name}, &Options{Length: 3, MinFrequency: 2})
+	if err != nil || !reflect.DeepEqual(word, []string{"cat", "sat", "the"}) {
+		t.Errorf("expected words seen twice, read %v (%v)", word, err)
+	}
+
+	word, err = ReadWords([]string{name}, &Options{Length: 3, Top: 4})
+	if err != nil || !reflect.DeepEqual(word, []string{"cat", "dog", "sat", "the"}) {
+		t.Errorf("expected four most frequent words, read %v (%v)", word, err)
+	}
+
+	if _, err := ReadWords([]string{name}, &Options{MinFrequency: 4}); err == nil {
+		t.Errorf("expected no words seen four times")
+	}
+}
+
+func TestGraphReadErrors(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "text")
+	if err := os.WriteFile(name, []byte("cat dog\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	// the words of the files read are returned along with the error of the others
+	word, err := ReadWords([]string{name, missing}, nil)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) || !reflect.DeepEqual(word, []string{"cat", "dog"}) {
+		t.Errorf("expected words of readable file and not exist error, read %v (%v)", word, err)
+	}
+	count, err := CountWords([]string{missing, name}, nil)
+	if err == nil || len(count) != 2 {
+		t.Errorf("expected counts of readable file and error, counted %v (%v)", count, err)
+	}
+	if word, err := ReadWords([]string{missing}, nil); err == nil || word != nil {
+		t.Errorf("expected error and no words, read %v", word)
+	}
+	if _, err := ReadGraph([]string{name, missing}, nil); err == nil {
+		t.Errorf("expected graph of unreadable file to fail")
+	}
+
+	// a file failing partway adds none of the words read before its error
+	var text bytes.Buffer
+	for i := 0; i < 100000; i++ {
+		fmt.Fprintf(&text, "%c%c%c%c ", 'a'+i%26, 'a'+i/26%26, 'a'+i/676%26, 'a'+i/17576%26)
+	}
+	z := gzipped(text.Bytes())
+	truncated := filepath.Join(t.TempDir(), "truncated.gz")
+	if err := os.WriteFile(truncated, z[:len(z)/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+	word, err = ReadWords([]string{name, truncated}, nil)
+	if err == nil || !reflect.DeepEqual(word, []string{"cat", "dog"}) {
+		t.Errorf("expected words of readable file and error, read %d words (%v)", len(word), err)
+	}
+	count, err = CountWords([]string{truncated, name}, nil)
+	if err == nil || len(count) != 2 {
+		t.Errorf("expected counts of readable file and error, counted %d (%v)", len(count), err)
+	}
+}
+
+func TestGraphLongWords(t *testing.T) {
+	// long compounds, linked as shorter words are, in the same and mixed scripts
+	word := []string{
+		"donaudampfschiffahrt", "donaudampfschifffahrt", "donaudampfschiffohrt",
+		"methylcyclopentadiene", "methylcyclopentadienes", "methylcyclopentadienyl",
+		"ωmethylcyclopentadiene",
+	}
+	sort.Strings(word)
+	g, err := NewGraph(word, &Options{Indel: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ladder, err := g.Ladder("donaudampfschiffohrt", "donaudampfschifffahrt")
+	if err != nil || len(ladder) != 3 {
+		t.Errorf("expected 2-step ladder, found %v (%v)", ladder, err)
+	}
+	ladder, err = g.Ladder("ωmethylcyclopentadiene", "methylcyclopentadienes")
+	if err != nil || len(ladder) != 3 {
+		t.Errorf("expected 2-step ladder, found %v (%v)", ladder, err)
+	}
+
+	// variations keyed by string and by packed letters agree
+	word, err = ReadWords([]string{"words/webster-3", "words/webster-4"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	packed := newVariants(word, true)
+	if !packed.packed {
+		t.Fatalf("expected packed variations of lower case words")
+	}
+	masked := packed
+	masked.packed = false
+	for pos := 0; pos < packed.widest; pos++ {
+		packed.find(pos)
+		masked.find(pos)
+		if p, m := sharedVariations(&packed), sharedVariations(&masked); len(p) == 0 || !reflect.DeepEqual(p, m) {
+			t.Errorf("packed and string variations at position %d differ", pos)
+		}
+	}
+
+	// words of one length are radix sorted, in the same order as by comparison
+	word, err = ReadWords([]string{"words/webster-4"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	radix := newVariants(word, false)
+	if !radix.fixed {
+		t.Fatalf("expected variations of words of one length")
+	}
+	for pos := 0; pos < radix.widest; pos++ {
+		radix.find(pos)
+		sorted := radix
+		sorted.v = append([]variant(nil), radix.v...)
+		sort.Sort(&sorted)
+		if !reflect.DeepEqual(radix.v, sorted.v) {
+			t.Errorf("radix and comparison sorted variations at position %d differ", pos)
+		}
+	}
+}
+
+// the position and words of each shared variation, with INFINITY before the short
+// words, in order
+func sharedVariations(s *variants) []Indexes {
+	var list []Indexes
+	s.each(func(pos int, long, short []variant) {
+		l := Indexes{Index(pos)}
+		for _, v := range long {
+			l = append(l, v.word)
+		}
+		l = append(l, INFINITY)
+		for _, v := range short {
+			l = append(l, v.word)
+		}
+		list = append(list, l)
+	})
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return list
+}
+
+func TestGraphParallelPairs(t *testing.T) {
+	word, err := ReadWords([]string{"words/webster-3", "words/webster-4", "words/webster-5"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(procs int) { MaxProcs = procs }(MaxProcs)
+	for _, opt := range []*Options{{}, {Indel: true}, {Indel: true, Anagram: true}} {
+		MaxProcs = 1
+		adj1, tag1 := findPairs(word, opt)
+		MaxProcs = 7
+		adj7, tag7 := findPairs(word, opt)
+		if !reflect.DeepEqual(adj1, adj7) || !reflect.DeepEqual(tag1, tag7) {
+			t.Errorf("%+v: pairs found by 1 and 7 workers differ", *opt)
+		}
+	}
+}
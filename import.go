@@ -0,0 +1,302 @@
+package ladder
+
+/*
+ * import.go -- build graphs from graph files rather than word lists
+ */
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReadEdgeList reads an undirected graph from r, one edge per line as two node
+// names separated by white space. Further fields, such as the rule written by
+// WriteEdgeList or a weight, are ignored, as are blank lines and everything after
+// a '#' or '%'. A line with a single name adds a node without edges. The names
+// become the words of the graph and the edges are tagged as Link.
+func ReadEdgeList(r io.Reader) (*Graph, error) {
+	index := make(map[string]Index)
+	var name []string
+	node := func(s string) Index {
+		n, ok := index[s]
+		if !ok {
+			n = Index(len(name))
+			index[s] = n
+			name = append(name, s)
+		}
+		return n
+	}
+
+	var edge [][2]Index
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.IndexAny(text, "#%"); i >= 0 {
+			text = text[:i]
+		}
+		field := strings.Fields(text)
+		switch len(field) {
+		case 0:
+		case 1:
+			node(field[0])
+		default:
+			edge = append(edge, [2]Index{node(field[0]), node(field[1])})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return newLinkedGraph(name, edge)
+}
+
+// ReadDIMACS reads an undirected graph from r in the DIMACS format of the graph
+// coloring and clique challenges: a problem line "p edge n m" (or "p col n m")
+// followed by edge lines "e u v" naming nodes 1 to n, with comment lines that
+// begin with 'c'. The node numbers become the words of the graph, which keep the
+// file's numbering: node 10 has Index 9 and follows node 9.
+func ReadDIMACS(r io.Reader) (*Graph, error) {
+	nodes := -1
+	var edge [][2]Index
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		field := strings.Fields(scanner.Text())
+		if len(field) == 0 || field[0] == "c" {
+			continue
+		}
+		switch {
+		case field[0] == "p" && len(field) >= 3 && nodes < 0:
+			n, err := strconv.Atoi(field[2])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("line %d: bad node count %q", line, field[2])
+			}
+			nodes = n
+		case field[0] == "e" && len(field) >= 3 && nodes >= 0:
+			u, err1 := nodeNumber(field[1], nodes)
+			v, err2 := nodeNumber(field[2], nodes)
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("line %d: edge %s %s must join nodes 1 to %d", line, field[1], field[2], nodes)
+			}
+			edge = append(edge, [2]Index{u, v})
+		default:
+			return nil, fmt.Errorf("line %d: expected \"p edge nodes edges\" and then \"e u v\" lines", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if nodes < 0 {
+		return nil, fmt.Errorf("no problem line")
+	}
+	return newNumberedGraph(nodes, edge)
+}
+
+// ReadMatrixMarket reads an undirected graph from r in the Matrix Market coordinate
+// format: a "%%MatrixMarket matrix coordinate" banner, a size line of rows, columns,
+// and entries, and then an entry line "i j [value]" for each edge between nodes i
+// and j, numbered from 1. The matrix must be square; values and the symmetry of the
+// banner are ignored since every entry is taken to be an undirected edge, and
+// diagonal entries are dropped. The node numbers become the words of the graph,
+// which keep the file's numbering as in ReadDIMACS.
+func ReadMatrixMarket(r io.Reader) (*Graph, error) {
+	scanner := bufio.NewScanner(r)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("empty file")
+	}
+	banner := strings.Fields(strings.ToLower(scanner.Text()))
+	if len(banner) < 3 || banner[0] != "%%matrixmarket" || banner[1] != "matrix" || banner[2] != "coordinate" {
+		return nil, fmt.Errorf("line 1: expected \"%%%%MatrixMarket matrix coordinate\" banner")
+	}
+
+	nodes := -1
+	var edge [][2]Index
+	for line := 2; scanner.Scan(); line++ {
+		field := strings.Fields(scanner.Text())
+		if len(field) == 0 || strings.HasPrefix(field[0], "%") {
+			continue
+		}
+		if nodes < 0 {
+			if len(field) != 3 || field[0] != field[1] {
+				return nil, fmt.Errorf("line %d: expected \"rows columns entries\" of a square matrix", line)
+			}
+			n, err := strconv.Atoi(field[0])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("line %d: bad matrix size %q", line, field[0])
+			}
+			nodes = n
+			continue
+		}
+		if len(field) < 2 {
+			return nil, fmt.Errorf("line %d: expected \"row column [value]\"", line)
+		}
+		u, err1 := nodeNumber(field[0], nodes)
+		v, err2 := nodeNumber(field[1], nodes)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("line %d: entry %s %s must be within rows and columns 1 to %d", line, field[0], field[1], nodes)
+		}
+		edge = append(edge, [2]Index{u, v})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if nodes < 0 {
+		return nil, fmt.Errorf("no size line")
+	}
+	return newNumberedGraph(nodes, edge)
+}
+
+// LoadGraph reads a graph from the named file in the given format: "edges" for an
+// edge list, "dimacs", or "mtx" for Matrix Market. An empty format is inferred from
+// the file's extension, ignoring any .gz, .bz2, or .zst: .mtx is Matrix Market,
+// .dimacs, .col, and .clq are DIMACS, and any other is an edge list. The file is
+// decompressed and may be standard input, as by OpenSource. A graph file gives its
+// nodes and edges, so of the options only Verbose and the Normalizer, used to find
+// words, apply; those selecting and linking words must be zero.
+func LoadGraph(name, format string, opt *Options) (g *Graph, err error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+	if opt.Length != 0 || opt.MinFrequency != 0 || opt.Top != 0 || opt.Indel || opt.Anagram {
+		return nil, errors.New("word length, frequency, and linking options do not apply to a graph file")
+	}
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(uncompressedName(name))) {
+		case ".mtx":
+			format = "mtx"
+		case ".dimacs", ".col", ".clq":
+			format = "dimacs"
+		default:
+			format = "edges"
+		}
+	}
+	var read func(io.Reader) (*Graph, error)
+	switch format {
+	case "edges":
+		read = ReadEdgeList
+	case "dimacs":
+		read = ReadDIMACS
+	case "mtx":
+		read = ReadMatrixMarket
+	default:
+		return nil, fmt.Errorf("unknown graph format %q (want edges, dimacs, or mtx)", format)
+	}
+
+	file, err := OpenSource(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := file.Close(); e != nil && err == nil {
+			g, err = nil, fmt.Errorf("%s: %v", name, e)
+		}
+	}()
+
+	g, err = read(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	g.opt = *opt
+	if opt.Verbose >= 1 {
+		nodes, edges := g.Len(), g.Edges()
+		log.Printf("read %d node%s and %d edge%s from %s file %s", nodes, plural(nodes), edges, plural(edges), format, name)
+	}
+	return g, nil
+}
+
+// parse a node number in 1..nodes as an index from zero
+func nodeNumber(s string, nodes int) (Index, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > nodes {
+		return 0, fmt.Errorf("bad node number %q", s)
+	}
+	return Index(n - 1), nil
+}
+
+// names of nodes numbered 1 to n
+func numberNames(n int) []string {
+	name := make([]string, n)
+	for i := range name {
+		name[i] = strconv.Itoa(i + 1)
+	}
+	return name
+}
+
+// Build a graph of named nodes joined by undirected edges, which may repeat or be
+// given in either direction. The nodes are renumbered in alphabetical order of
+// their names, which become the words of the graph, and self loops are dropped.
+func newLinkedGraph(name []string, edge [][2]Index) (*Graph, error) {
+	if len(name) < 1 {
+		return nil, fmt.Errorf("no nodes found")
+	}
+
+	// renumber nodes by name
+	order := make(Indexes, len(name))
+	for i := range order {
+		order[i] = Index(i)
+	}
+	sort.Slice(order, func(i, j int) bool { return name[order[i]] < name[order[j]] })
+	word := make([]string, len(name))
+	index := make(Indexes, len(name))
+	for i, n := range order {
+		word[i] = name[n]
+		index[n] = Index(i)
+	}
+	return linkNodes(word, index, edge), nil
+}
+
+// Build a graph of nodes numbered 1 to n joined by edges as newLinkedGraph does,
+// but keeping their numbers, so that node 10 follows node 9 rather than node 1 as
+// it would in alphabetical order. Find looks the numbers up by value.
+func newNumberedGraph(n int, edge [][2]Index) (*Graph, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("no nodes found")
+	}
+	index := make(Indexes, n)
+	for i := range index {
+		index[i] = Index(i)
+	}
+	g := linkNodes(numberNames(n), index, edge)
+	g.numbered = true
+	return g, nil
+}
+
+// link the nodes of edge, renumbered by index, both ways as the words of a graph,
+// ordering and removing repeated links and dropping self loops
+func linkNodes(word []string, index Indexes, edge [][2]Index) *Graph {
+	pair := make([]Indexes, len(word))
+	for _, e := range edge {
+		u, v := index[e[0]], index[e[1]]
+		if u != v {
+			pair[u] = append(pair[u], v)
+			pair[v] = append(pair[v], u)
+		}
+	}
+	for w := range pair {
+		sort.Sort(pair[w])
+		p := pair[w]
+		n := 0
+		for i := range p {
+			if i == 0 || p[i] != p[n-1] {
+				p[n] = p[i]
+				n++
+			}
+		}
+		pair[w] = p[:n]
+	}
+	adj := newAdjacency(pair)
+	tag := make([]Edge, len(adj.link))
+	for i := range tag {
+		tag[i] = newEdge(Link, 0)
+	}
+
+	return &Graph{word: word, adj: adj, tag: tag}
+}
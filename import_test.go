@@ -0,0 +1,139 @@
+package ladder
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// imported graphs give the same sums as the hand-built test graphs they describe
+func TestImport(t *testing.T) {
+	for _, test := range weightedGraphs {
+		node, a, component := test.build()
+		pairs, _, sum := sumAllSourcesShortestPathsV1(node, newAdjacency(a), component)
+
+		// edge list, by way of WriteEdgeList
+		var b bytes.Buffer
+		if err := namedGraph(test.build).WriteEdgeList(&b, -1); err != nil {
+			t.Fatal(err)
+		}
+		g, err := ReadEdgeList(&b)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if p, _, s := g.SumShortestPaths(); p != pairs || s != sum {
+			t.Errorf("%s edge list: expected (%d, %d), computed (%d, %d)", test.name, pairs, sum, p, s)
+		}
+
+		// DIMACS, and Matrix Market with each edge given in both directions
+		var dimacs, mtx bytes.Buffer
+		fmt.Fprintf(&dimacs, "c %s\np edge %d %d\n", test.name, len(node), 0)
+		fmt.Fprintf(&mtx, "%%%%MatrixMarket matrix coordinate pattern general\n%% %s\n%d %d %d\n", test.name, len(node), len(node), 0)
+		for w, p := range a {
+			for _, wn := range p {
+				if Index(w) < wn {
+					fmt.Fprintf(&dimacs, "e %d %d\n", w+1, wn+1)
+				}
+				fmt.Fprintf(&mtx, "%d %d\n", w+1, wn+1)
+			}
+		}
+		for _, f := range []struct {
+			format string
+			read   func() (*Graph, error)
+		}{
+			{"DIMACS", func() (*Graph, error) { return ReadDIMACS(&dimacs) }},
+			{"Matrix Market", func() (*Graph, error) { return ReadMatrixMarket(&mtx) }},
+		} {
+			g, err := f.read()
+			if err != nil {
+				t.Fatalf("%s %s: %v", test.name, f.format, err)
+			}
+			if g.Len() != len(node) || g.Edges() != namedGraph(test.build).Edges() {
+				t.Errorf("%s %s: expected %d nodes, read %d", test.name, f.format, len(node), g.Len())
+			}
+			if p, _, s := g.SumShortestPaths(); p != pairs || s != sum {
+				t.Errorf("%s %s: expected (%d, %d), computed (%d, %d)", test.name, f.format, pairs, sum, p, s)
+			}
+		}
+	}
+}
+
+func TestImportEdgeList(t *testing.T) {
+	g, err := ReadEdgeList(strings.NewReader("# a path, a loop, and a lone node\nB a\na B 1\nc b\nb B\nD D\nE\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// names sort as B D E a b c
+	if g.Len() != 6 || g.Edges() != 3 || len(g.Components()) != 3 {
+		t.Errorf("expected 6 nodes, 3 edges, and 3 components, found %d, %d, and %d", g.Len(), g.Edges(), len(g.Components()))
+	}
+	ladder, err := g.Ladder("a", "c")
+	if err != nil || strings.Join(ladder, " ") != "a B b c" {
+		t.Errorf("expected ladder a B b c, found %v (%v)", ladder, err)
+	}
+	step, err := g.Steps(ladder)
+	if err != nil || step[0].Kind() != Link || step[0].String() != "link" {
+		t.Errorf("expected link steps, found %v (%v)", step, err)
+	}
+
+	for _, bad := range []string{"p edge 2 1\ne 1 3\n", "e 1 2\n", "c no problem\n"} {
+		if _, err := ReadDIMACS(strings.NewReader(bad)); err == nil {
+			t.Errorf("expected error reading DIMACS %q", bad)
+		}
+	}
+	for _, bad := range []string{"", "%%MatrixMarket matrix array real general\n", "%%MatrixMarket matrix coordinate pattern general\n2 3 1\n"} {
+		if _, err := ReadMatrixMarket(strings.NewReader(bad)); err == nil {
+			t.Errorf("expected error reading Matrix Market %q", bad)
+		}
+	}
+}
+
+func TestImportNumbered(t *testing.T) {
+	// a path 1-2-...-12, so numeric and alphabetical orders differ
+	var dimacs, mtx strings.Builder
+	fmt.Fprintf(&dimacs, "p edge 12 11\n")
+	fmt.Fprintf(&mtx, "%%%%MatrixMarket matrix coordinate pattern symmetric\n12 12 11\n")
+	for i := 1; i < 12; i++ {
+		fmt.Fprintf(&dimacs, "e %d %d\n", i, i+1)
+		fmt.Fprintf(&mtx, "%d %d\n", i+1, i)
+	}
+	for _, f := range []struct {
+		format string
+		read   func() (*Graph, error)
+	}{
+		{"DIMACS", func() (*Graph, error) { return ReadDIMACS(strings.NewReader(dimacs.String())) }},
+		{"Matrix Market", func() (*Graph, error) { return ReadMatrixMarket(strings.NewReader(mtx.String())) }},
+	} {
+		g, err := f.read()
+		if err != nil {
+			t.Fatalf("%s: %v", f.format, err)
+		}
+		for i, s := range g.Words() {
+			if s != strconv.Itoa(i+1) {
+				t.Fatalf("%s: expected word %d to be %d, found %q", f.format, i, i+1, s)
+			}
+		}
+		w, ok := g.Find("10")
+		if !ok || w != 9 {
+			t.Errorf("%s: expected to find 10 at index 9, found %d (%v)", f.format, w, ok)
+		}
+		var near []string
+		for _, n := range g.Neighbors(w) {
+			near = append(near, g.Word(n))
+		}
+		if strings.Join(near, " ") != "9 11" {
+			t.Errorf("%s: expected 10 to neighbor 9 11, found %v", f.format, near)
+		}
+		for _, s := range []string{"0", "13", "010", "+10", "ten"} {
+			if _, ok := g.Find(s); ok {
+				t.Errorf("%s: expected not to find %q", f.format, s)
+			}
+		}
+		ladder, err := g.Ladder("2", "12")
+		if err != nil || len(ladder) != 11 || ladder[8] != "10" {
+			t.Errorf("%s: expected ladder 2 to 12 through 10, found %v (%v)", f.format, ladder, err)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package ladder
+
+/*
+ * indices.go -- distance-based topological indices of the word graph
+ */
+
+// Indices are distance-based topological indices of a graph or of one of its
+// components, summed over the unordered pairs of distinct connected words.
+type Indices struct {
+	Words             int     `json:"words"`               // number of words
+	Pairs             int     `json:"pairs"`               // ordered pairs of distinct connected words
+	Wiener            int     `json:"wiener"`              // sum of distances d(u,v), half the summed lengths
+	Harary            float64 `json:"harary"`              // sum of reciprocal distances 1/d(u,v)
+	DegreeDistance    int     `json:"degree_distance"`     // sum of (deg(u) + deg(v)) d(u,v)
+	AveragePathLength float64 `json:"average_path_length"` // mean distance between connected words
+	Efficiency        float64 `json:"efficiency"`          // mean reciprocal distance over every pair of distinct words
+}
+
+// Indices returns the topological indices of the graph as a whole and of each of its
+// components, in the order of Components. Disconnected pairs of words count toward
+// the efficiency (as zero) but not toward the average path length.
+func (g *Graph) Indices() (Indices, []Indices) {
+	component := findIndicesV2(g.word, g.adj, g.Components())
+	return totalIndices(component), component
+}
+
+// sum the indices of components into those of the graph they make up
+func totalIndices(component []Indices) Indices {
+	var t Indices
+	for _, c := range component {
+		t.Words += c.Words
+		t.Pairs += c.Pairs
+		t.Wiener += c.Wiener
+		t.Harary += c.Harary
+		t.DegreeDistance += c.DegreeDistance
+	}
+	t.finish()
+	return t
+}
+
+// derive the averages from the sums
+func (x *Indices) finish() {
+	if x.Pairs > 0 {
+		x.AveragePathLength = float64(2*x.Wiener) / float64(x.Pairs)
+	}
+	if x.Words > 1 {
+		x.Efficiency = 2 * x.Harary / float64(x.Words*(x.Words-1))
+	}
+}
+
+// Accumulated results of BFS from some of a component's words: the histogram of
+// distances and the sum over sources u of deg(u) times the summed distances from u.
+// The Wiener and Harary indices follow from the histogram and the degree distance
+// index is the weighted sum, since each pair's term is split between its two ends:
+// deg(u) d(u,v) in the sum from u and deg(v) d(u,v) in the sum from v.
+type indexSum struct {
+	histogram []int
+	weighted  int
+}
+
+func (s *indexSum) add(t indexSum) {
+	addHistogram(s.histogram, t.histogram)
+	s.weighted += t.weighted
+}
+
+// the indices of component c given the results of BFS from each of its words
+func (s indexSum) indices(c Component) Indices {
+	x := Indices{Words: c.words, Pairs: c.words * (c.words - 1), DegreeDistance: s.weighted}
+	for d := 1; d < len(s.histogram); d++ {
+		x.Wiener += d * s.histogram[d]
+		x.Harary += float64(s.histogram[d]) / float64(d)
+	}
+	x.Wiener /= 2 // the histogram counts ordered pairs
+	x.Harary /= 2
+	x.finish()
+	return x
+}
+
+// Find the topological indices of each component.
+func findIndicesV1(word []string, adj adjacency, component []Component) []Indices {
+	index := make([]Indices, len(component))
+	if len(component) > 0 {
+		distance := make([]Index, len(word))
+		queue := make([]Index, component[0].words)
+		done := make([]bool, len(word))
+		histogram := make([]int, component[0].words)
+		for cn, c := range component {
+			index[cn] = ssspIndicesSerial(adj, c, distance, queue, done, histogram)
+		}
+	}
+	return index
+}
+
+// BFS from each word of component c in turn, using the given scratch space
+func ssspIndicesSerial(adj adjacency, c Component, distance, queue []Index, done []bool, histogram []int) Indices {
+	s := indexSum{histogram: histogram[:c.words]}
+	for d := range s.histogram {
+		s.histogram[d] = 0
+	}
+	for _, w := range c.word {
+		sum, _ := ssspBFS(c.word, adj, w, distance, queue, done, s.histogram)
+		s.weighted += adj.degree(w) * sum
+	}
+	return s.indices(c)
+}
+
+// Parallel version of findIndicesV1. Large components are solved one at a time by
+// parallel BFS from their words and smaller ones in parallel, one to a worker.
+func findIndicesV2(word []string, adj adjacency, component []Component) []Indices {
+	var i, j int
+	components := len(component)
+
+	// optimization -- skip parallel framework overhead when nothing but simple tasks
+	if components > 0 && component[0].words <= 16 {
+		return findIndicesV1(word, adj, component)
+	}
+	index := make([]Indices, components)
+
+	// solve large problems sequentially, using parallel workers within each
+	for ; i < components && component[i].words >= BREAKPOINT; i++ {
+		index[i] = ssspIndicesWordsParallel(word, adj, component[i])
+	}
+
+	// solve medium problems in parallel, using a single worker for each
+	for j = i; j < components && component[j].words > 2; j++ {
+	}
+	if i < j {
+		ssspIndicesComponentsParallel(word, adj, component[i:j], index[i:j])
+		i = j
+	}
+
+	// solve small (nodes <= 2) problems directly
+	for ; i < components; i++ {
+		c := component[i]
+		switch {
+		case c.words == 1: // single aloof word
+			index[i] = Indices{Words: 1}
+		case c.words == 2: // single pair of words at distance one, each of degree one
+			index[i] = Indices{Words: 2, Pairs: 2, Wiener: 1, Harary: 1, DegreeDistance: 2}
+			index[i].finish()
+		default:
+			panic("internal error: small problem with more than 2 nodes")
+		}
+	}
+	return index
+}
+
+// Each worker accumulates the results of its sources and sends them when done.
+func ssspIndicesWordsParallel(word []string, adj adjacency, c Component) Indices {
+	tasks := make(chan Index)
+	results := make(chan indexSum)
+
+	// start workers
+	workers := minInt(c.words, MaxProcs)
+	for i := 0; i < workers; i++ {
+		go func(id int, in chan Index, out chan indexSum) {
+			distance := make(Indexes, len(word))
+			queue := make(Indexes, len(c.word))
+			done := make([]bool, len(word))
+			s := indexSum{histogram: make([]int, c.words)}
+			for w := range in {
+				sum, _ := ssspBFS(c.word, adj, w, distance, queue, done, s.histogram)
+				s.weighted += adj.degree(w) * sum
+			}
+			out <- s
+		}(i, tasks, results)
+	}
+
+	// start dispatcher
+	go func(out chan Index) {
+		for _, w := range c.word {
+			out <- w
+		}
+		close(out)
+	}(tasks)
+
+	// harvest results from workers
+	s := indexSum{histogram: make([]int, c.words)}
+	for i := 0; i < workers; i++ {
+		s.add(<-results)
+	}
+	close(results)
+	return s.indices(c)
+}
+
+// Find the indices of each component in parallel, one component to a worker.
+func ssspIndicesComponentsParallel(word []string, adj adjacency, component []Component, index []Indices) {
+	tasks := make(chan int)
+	results := make(chan bool)
+
+	// start workers
+	workers := minInt(len(component), MaxProcs)
+	for k := 0; k < workers; k++ {
+		go func(id int, in chan int, out chan bool) {
+			distance := make(Indexes, len(word))
+			done := make([]bool, len(word))
+			queue := make(Indexes, component[0].words) // components are sorted largest first
+			histogram := make([]int, component[0].words)
+			for cn := range in {
+				index[cn] = ssspIndicesSerial(adj, component[cn], distance, queue, done, histogram)
+				out <- true
+			}
+		}(k, tasks, results)
+	}
+
+	// dispatch tasks to workers
+	go func(out chan int) {
+		for cn := range component {
+			out <- cn
+		}
+		close(out)
+	}(tasks)
+
+	// harvest results from workers
+	for _ = range component {
+		<-results
+	}
+	close(results)
+}
@@ -0,0 +1,160 @@
+package ladder
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+// harmonic number H_n
+func harmonic(n int) float64 {
+	h := 0.0
+	for k := 1; k <= n; k++ {
+		h += 1 / float64(k)
+	}
+	return h
+}
+
+// Wiener, Harary, and degree distance indices of the test graphs, by formula. The
+// degree distance is the sum over words of degree times summed distance to others.
+var indexGraphs = []struct {
+	name   string
+	build  Builder
+	wiener int
+	harary float64
+	dd     int
+}{
+	// P_n: W = (n^3-n)/6, H = n H_(n-1) - (n-1), DD = 4W - n(n-1) as the ends have degree 1
+	{"path", func() ([]string, []Indexes, []Component) { return buildPathGraph(30) }, (30*30*30 - 30) / 6, 30*harmonic(29) - 29, 4*(30*30*30-30)/6 - 30*29},
+	// C_n, n = 2k+1: W = n(n^2-1)/8, H = n H_k, DD = 4W as every degree is 2
+	{"cycle-odd", func() ([]string, []Indexes, []Component) { return buildCycleGraph(31) }, 31 * (31*31 - 1) / 8, 31 * harmonic(15), 4 * 31 * (31*31 - 1) / 8},
+	// C_n, n = 2k: W = n^3/8, H = n H_(k-1) + n/2k
+	{"cycle-even", func() ([]string, []Indexes, []Component) { return buildCycleGraph(30) }, 30 * 30 * 30 / 8, 30*harmonic(14) + 1, 4 * 30 * 30 * 30 / 8},
+	// K_n: W = H = n(n-1)/2, DD = n(n-1)^2
+	{"complete", func() ([]string, []Indexes, []Component) { return buildCompleteGraph(20) }, 20 * 19 / 2, 20 * 19 / 2, 20 * 19 * 19},
+	// star of n: W = (n-1)^2, H = (n-1) + (n-1)(n-2)/4, DD = (n-1)(3n-4)
+	{"star", func() ([]string, []Indexes, []Component) { return buildStarGraph(25) }, 24 * 24, 24 + 24*23/4.0, 24 * (3*25 - 4)},
+	// wheel with m spokes: W = m(m-1), H = 2m + (m(m-1)/2 - m)/2, DD = 7m^2 - 9m
+	{"wheel", func() ([]string, []Indexes, []Component) { return buildWheelGraph(40) }, 39 * 38, 2*39 + (39*38/2-39)/2.0, 7*39*39 - 9*39},
+	// K_(a,b): W = ab + 2(C(a,2) + C(b,2)), H = ab + (C(a,2) + C(b,2))/2, DD = ab(3a+3b-4)
+	{"bipartite", func() ([]string, []Indexes, []Component) { return buildCompleteBipartiteGraph(12, 5) },
+		12*5 + 2*(12*11/2+5*4/2), 12*5 + (12*11/2+5*4/2)/2.0, 12 * 5 * (3*12 + 3*5 - 4)},
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestIndicesAnalytic(t *testing.T) {
+	for _, test := range indexGraphs {
+		node, a, component := test.build()
+		n := len(node)
+		for version, index := range [][]Indices{findIndicesV1(node, newAdjacency(a), component), findIndicesV2(node, newAdjacency(a), component)} {
+			x := index[0]
+			if x.Words != n || x.Pairs != n*(n-1) || x.Wiener != test.wiener || !closeTo(x.Harary, test.harary) ||
+				x.DegreeDistance != test.dd || !closeTo(x.AveragePathLength, float64(2*test.wiener)/float64(n*(n-1))) ||
+				!closeTo(x.Efficiency, 2*test.harary/float64(n*(n-1))) {
+				t.Errorf("%s V%d: expected W=%d, H=%g, DD=%d, computed %+v",
+					test.name, version+1, test.wiener, test.harary, test.dd, x)
+			}
+		}
+	}
+}
+
+// reference indices by Floyd-Warshall, for graphs that are awkward to work by hand
+func floydIndices(a []Indexes) Indices {
+	n := len(a)
+	d := make([][]int, n)
+	for i := range d {
+		d[i] = make([]int, n)
+		for j := range d[i] {
+			if i != j {
+				d[i][j] = INFINITY
+			}
+		}
+		for _, j := range a[i] {
+			d[i][j] = 1
+		}
+	}
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				d[i][j] = minInt(d[i][j], d[i][k]+d[k][j])
+			}
+		}
+	}
+	x := Indices{Words: n}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if d[i][j] < INFINITY {
+				x.Pairs += 2
+				x.Wiener += d[i][j]
+				x.Harary += 1 / float64(d[i][j])
+				x.DegreeDistance += (len(a[i]) + len(a[j])) * d[i][j]
+			}
+		}
+	}
+	x.finish()
+	return x
+}
+
+// disjoint union of test graphs, as components of one graph, largest first
+func unionGraph(build ...Builder) ([]string, []Indexes, []Component) {
+	var node []string
+	var a []Indexes
+	var component []Component
+	for _, b := range build {
+		n, p, c := b()
+		base := Index(len(node))
+		node = append(node, n...)
+		for _, list := range p {
+			shifted := make(Indexes, len(list))
+			for i, w := range list {
+				shifted[i] = base + w
+			}
+			a = append(a, shifted)
+		}
+		for _, comp := range c {
+			shifted := make(Indexes, comp.words)
+			for i, w := range comp.word {
+				shifted[i] = base + w
+			}
+			component = append(component, Component{shifted, comp.words})
+		}
+	}
+	sort.Sort(Components(component))
+	return node, a, component
+}
+
+// equal indices, but for rounding
+func sameIndices(a, b Indices) bool {
+	return a.Words == b.Words && a.Pairs == b.Pairs && a.Wiener == b.Wiener && closeTo(a.Harary, b.Harary) &&
+		a.DegreeDistance == b.DegreeDistance && closeTo(a.AveragePathLength, b.AveragePathLength) &&
+		closeTo(a.Efficiency, b.Efficiency)
+}
+
+func TestIndices(t *testing.T) {
+	for _, test := range weightedGraphs {
+		node, a, component := test.build()
+		x := findIndicesV2(node, newAdjacency(a), component)[0]
+		if !sameIndices(x, floydIndices(a)) {
+			t.Errorf("%s: expected %+v, computed %+v", test.name, floydIndices(a), x)
+		}
+	}
+
+	// a graph of several components, including a pair and an aloof word
+	var build []Builder
+	for _, test := range weightedGraphs {
+		build = append(build, test.build)
+	}
+	build = append(build,
+		func() ([]string, []Indexes, []Component) { return buildPathGraph(2) },
+		func() ([]string, []Indexes, []Component) { return buildCompleteGraph(1) })
+	node, a, component := unionGraph(build...)
+	expected := floydIndices(a)
+	for version, index := range [][]Indices{findIndicesV1(node, newAdjacency(a), component), findIndicesV2(node, newAdjacency(a), component)} {
+		if total := totalIndices(index); !sameIndices(total, expected) {
+			t.Errorf("union V%d: expected %+v, computed %+v", version+1, expected, total)
+		}
+	}
+}
@@ -1,4 +1,7 @@
-package main
+// Package ladder builds the word graph of Lewis Carroll's Doublets puzzle, where
+// words are linked when they differ in a single letter, and finds and measures the
+// shortest ladders of words between them.
+package ladder
 
 /*
  * ladder.go -- count shortest paths among words in Doublet puzzle
@@ -6,20 +9,18 @@ package main
 
 import (
 	"bufio"
-	"flag"
+	"errors"
 	"fmt"
+	"io"
 	"log"
-	"os"
+	"math"
+	"math/big"
+	"math/bits"
 	"runtime"
 	"sort"
-	"strings"
-	"syscall"
-	"time"
 	"unicode/utf8"
 )
 
-const WIDEST = 12
-
 type Index uint32 // array index type for node and edge lists
 const INFINITY = 1 << 30
 
@@ -29,221 +30,166 @@ func (a Indexes) Len() int           { return len(a) }
 func (a Indexes) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a Indexes) Less(i, j int) bool { return a[i] < a[j] }
 
-// flag processor global variables
-var wordsize, verbose int
-var timing bool
-var output string
-
-func init() {
-	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
-
-	flag.IntVar(&wordsize, "n", 0, "number of letters (zero means any)")
-	flag.BoolVar(&timing, "t", false, "time and report progress")
-	flag.IntVar(&verbose, "v", 0, "verbosity level")
-	flag.StringVar(&output, "o", "", "output wordset to file")
-}
-
 var MaxProcs = runtime.GOMAXPROCS(0)
 var NumCPU = runtime.NumCPU()
 
-func main() {
-	start := time.Now()
-	meter := NewMeter()
-
-	if verbose >= 1 {
-		log.Printf("execution begins")
-	}
-
-	flag.Parse()
-
-	// Read words from files named on the command line, or if none is given,
-	// from "/usr/share/dict/words". Each word will be a node in our graph.
-	filenames := flag.Args()
-	if len(filenames) == 0 { // set default file name
-		filenames = []string{"/usr/share/dict/words"}
-	}
-
-	word, runes := readWords(filenames, wordsize)
-	if timing {
-		meter.SetWork(float64(len(word))) // unique words/sec
-		log.Printf("%v read %v words", meter, len(word))
-	}
-
-	if output != "" {
-		writeWords(word, output)
-		if timing {
-			meter.SetWork(0)
-			log.Printf("%v wrote %v words", meter, len(word))
-		}
-	}
-
-	// Determine which word-to-word transformations are allowed by the rules
-	// of Lewis Carroll's Doublets puzzle. These are the graph's edges.
-	pair := findPairs(word, runes)
-	if timing {
-		sum := 0
-		for _, p := range pair {
-			sum += len(p)
-		}
-		sum /= 2
-		meter.SetWork(float64(sum)) // pairs/sec
-		log.Printf("%v find %v pairs", meter, sum)
-	}
-
-	// Determine graph's connected components. Each component is disconnected
-	// from the others so searching and counting are independent sub-problems.
-	component := findComponents(word, pair)
-	if timing {
-		meter.SetWork(float64(len(component))) // connected components/sec
-		log.Printf("%v find %v components", meter, len(component))
-	}
-
-	// count one shortest length path between each word pair in each component
-	var count, total int
-	if false {
-		count, total = sumAllSourcesShortestPathsV1(word, pair, component)
-		if timing {
-			meter.SetWork(float64(count)) // paths/sec
-			log.Printf("%v find %v paths", meter, count)
-		}
-		fmt.Printf("%12d word pairs\n", count)
-		fmt.Printf("%12d summed lengths of one shortest path per pair\n", total)
+// Read words from files and return a clean, ordered word list, along with any
+// error reading them as countWords does.
+func readWords(name []string, opt *Options) ([]string, error) {
+	count, err := countWords(name, opt)
+	if count == nil {
+		return nil, err
 	}
-
-	// count one shortest length path between each word pair in each component
-	if true {
-		count, total = sumAllSourcesShortestPathsV2(word, pair, component)
-		if timing {
-			meter.SetWork(float64(count)) // paths/sec
-			log.Printf("%v find %v paths", meter, count)
-		}
-		fmt.Printf("%12d word pairs\n", count)
-		fmt.Printf("%12d summed lengths of one shortest path per pair\n", total)
-	}
-
-	elapsed := float64(time.Now().Sub(start)) / 1e9
-	if verbose >= 1 {
-		log.Printf("execution ends, elapsed time = %.6f seconds", elapsed)
-	}
-	if timing {
-		fmt.Printf("# %12.6f %12d %12d %2d %6d %v\n", elapsed, count, total, wordsize, len(word), filenames)
+	word := make([]string, len(count))
+	for i, c := range count {
+		word[i] = c.Word
 	}
+	return word, err
 }
 
-// Read words from files and return a clean, ordered word list
-func readWords(name []string, length int) ([]string, int) {
+// count the occurrences of words in the named files, returning those selected by
+// the options in alphabetic order. Files that cannot be read, even partway, add no
+// words; their errors are joined in the error returned along with the words of the
+// others.
+func countWords(name []string, opt *Options) ([]WordCount, error) {
+	length, verbose := opt.Length, opt.Verbose
+
 	// interpret word length parameter
 	var minLength, maxLength int
 	switch {
 	case length == 0:
 		minLength = 1
-		maxLength = WIDEST
-	case 1 <= length && length <= WIDEST:
+		maxLength = math.MaxInt
+	case length > 0:
 		minLength = length
 		maxLength = length
 	default:
-		log.Fatalf("error: wordlength (%d) must be in 1..WIDEST (1..%d)", length, WIDEST)
+		return nil, fmt.Errorf("wordlength (%d) must not be negative", length)
 	}
 
 	names := len(name)
 	if verbose >= 1 {
 		switch {
 		case length == 0:
-			log.Printf("selecting words of length %d..%d from %d file%s\n", minLength, maxLength, names, plural(names))
+			log.Printf("selecting words of any length from %d file%s\n", names, plural(names))
 		default:
 			log.Printf("selecting %d-letter words from %d file%s\n", minLength, names, plural(names))
 		}
 	}
 
 	// gather words from files using a map
-	unique := make(map[string]struct{})
-	var totalAdded, totalLong, totalRead int
+	var unique map[string]int
+	var totalAdded, totalRead int
+	var readErr []error
 
-	// custom splitter for scanner using "all non-letters except apostrophe"
-	splitter := func(data []byte, atEOF bool) (advance int, token []byte, err error) {
-		return scanCutset(data, atEOF, " \t\n\r0123456789`~!@#$%^&*()-—_=+[{]}\\|;:\",<.>/?") // allow apostrophe
-	}
+	// split text into words and normalize them as configured
+	normalizer := &opt.Normalizer
 
-	runesAdded := 0
 	for _, n := range name {
-		var wordsAdded, wordsLong, wordsRead int
-
-		// access named file
-		file, err := os.Open(n)
+		var wordsAdded, wordsRead int
+		file := make(map[string]int) // counted apart, so that a file failing partway adds nothing
+
+		// access named file, or each file in the named archive
+		err := readMembers(n, func(member string, r io.Reader) error {
+			before := wordsRead
+			scanner := bufio.NewScanner(r)
+			scanner.Split(normalizer.Split)
+			for scanner.Scan() {
+				word := normalizer.Word(scanner.Text())
+
+				if l := utf8.RuneCountInString(word); minLength <= l && l <= maxLength {
+					file[word]++
+					wordsAdded++
+				}
+				wordsRead++
+			}
+			if verbose >= 2 && member != n {
+				log.Printf("  read %7d words from member %s", wordsRead-before, member)
+			}
+			return scanner.Err()
+		})
 		if err != nil {
-			log.Printf("%v: %v", n, err)
+			readErr = append(readErr, err)
 			continue
 		}
-		defer file.Close()
-
-		scanner := bufio.NewScanner(file)
-		scanner.Split(splitter)
-		for scanner.Scan() {
-			word := scanner.Text()
-			word = strings.Replace(word, "'", "", -1) // remove apostrophes ("o'clock" ==> "oclock")
-			word = strings.Replace(word, "’", "", -1) // remove apostrophes ("o'clock" ==> "oclock")
-			word = strings.ToLower(word)
-
-			switch l := utf8.RuneCountInString(word); {
-			case minLength <= l && l <= maxLength:
-				unique[word] = struct{}{}
-				wordsAdded++
-				runesAdded += l
-			case l > WIDEST:
-				wordsLong++
+		if unique == nil {
+			unique = file
+		} else {
+			for s, c := range file {
+				unique[s] += c
 			}
-			wordsRead++
 		}
 		totalAdded += wordsAdded
-		totalLong += wordsLong
 		totalRead += wordsRead
 
-		if wordsLong > 0 {
-			log.Printf("warning: skipped %6d words longer than WIDEST=%d runes in %s", wordsLong, WIDEST, n)
-		}
 		if verbose >= 1 {
 			log.Printf("  added %7d of %7d words from file %s", wordsAdded, wordsRead, n)
 		}
 	}
+	err := errors.Join(readErr...)
 	if len(unique) < 1 {
-		log.Fatal("error: no words found")
+		return nil, errors.Join(err, errors.New("no words found"))
 	}
 
-	// sort words into alphabetic order
-	word := make([]string, len(unique))
-	words := 0
-	for s := range unique {
-		word[words] = s
-		words++
+	// keep words seen often enough, and of those only the most frequent if asked
+	count := make([]WordCount, 0, len(unique))
+	for s, n := range unique {
+		if n >= opt.MinFrequency {
+			count = append(count, WordCount{s, n})
+		}
 	}
-	sort.Strings(word)
-
-	if totalLong > 0 {
-		log.Printf("skipped total of %6d words longer than WIDEST=%d runes", totalLong, WIDEST)
+	rare := len(unique) - len(count)
+	if opt.Top > 0 && opt.Top < len(count) {
+		sortByFrequency(count)
+		rare += len(count) - opt.Top
+		count = count[:opt.Top]
+	}
+	if len(count) < 1 {
+		return nil, errors.Join(err, fmt.Errorf("no words found at least %d times", opt.MinFrequency))
 	}
+
+	// sort words into alphabetic order
+	sort.Slice(count, func(i, j int) bool { return count[i].Word < count[j].Word })
+	words := len(count)
+
 	if verbose >= 1 {
-		log.Printf("read total of %d unique words (skipped %d repeated words)", words, totalAdded-words)
+		log.Printf("read total of %d unique words (skipped %d repeated words)", len(unique), totalAdded-len(unique))
+		if rare > 0 {
+			log.Printf("kept %d words (skipped %d less frequent words)", words, rare)
+		}
 	}
 	if verbose >= 2 {
+		word := make([]string, words)
+		for i, c := range count {
+			word[i] = c.Word
+		}
 		fmt.Println()
 		fmt.Printf("words:\n")
 		printWords(word)
 	}
-	return word, runesAdded
+	return count, err
+}
+
+// order words by descending count, then alphabetically
+func sortByFrequency(count []WordCount) {
+	sort.Slice(count, func(i, j int) bool {
+		a, b := count[i], count[j]
+		return a.Count > b.Count || (a.Count == b.Count && a.Word < b.Word)
+	})
 }
 
-// scanCutset is a version of strings.ScanWords that represents a split
-// function for a Scanner that returns each non-cutset-separated string
-// of text, with surrounding cutset characters deleted. It will never
-// return an empty string. It cannot be used directly with scanner, but
-// must be wrapped in another function to supply the cutset.
-func scanCutset(data []byte, atEOF bool, cutset string) (advance int, token []byte, err error) {
+// scanSeparated is a version of strings.ScanWords that represents a split
+// function for a Scanner that returns each string of text separated by
+// runes for which separates is true, with surrounding separators deleted.
+// It will never return an empty string. It cannot be used directly with
+// scanner, but must be wrapped in another function to supply separates.
+func scanSeparated(data []byte, atEOF bool, separates func(rune) bool) (advance int, token []byte, err error) {
 	// Skip leading spaces.
 	start := 0
 	for width := 0; start < len(data); start += width {
 		var r rune
 		r, width = utf8.DecodeRune(data[start:])
-		if !strings.ContainsRune(cutset, r) {
+		if !separates(r) {
 			break
 		}
 	}
@@ -254,7 +200,7 @@ func scanCutset(data []byte, atEOF bool, cutset string) (advance int, token []by
 	for width, i := 0, start; i < len(data); i += width {
 		var r rune
 		r, width = utf8.DecodeRune(data[i:])
-		if strings.ContainsRune(cutset, r) {
+		if separates(r) {
 			return i + width, data[start:i], nil
 		}
 	}
@@ -266,71 +212,115 @@ func scanCutset(data []byte, atEOF bool, cutset string) (advance int, token []by
 	return 0, nil, nil
 }
 
-func findPairs(word []string, runes int) []Indexes {
-	widest := widestString(word)
-	if widest > WIDEST {
-		log.Fatalf("constant 'WIDEST=%v' is too small, must be >= %v for chosen words", WIDEST, widest)
-	}
+// Find the pairs of words linked by the rules selected in opt. The result is the
+// adjacency listing, for each word, the words linked to it in order, along with the
+// tag of each such link.
+func findPairs(word []string, opt *Options) (adjacency, []Edge) {
+	// find the links of each "change one letter" variation position, and of anagrams,
+	// in parallel: each worker sorts the variations of the words at a position so
+	// that those sharing one are together and, with insertion and deletion, so are
+	// the shorter words that would match it if a letter were inserted there
+	s := newVariants(word, opt.Indel)
+	tasks := make(chan int)
+	results := make(chan edgeList)
+	const anagrams = -1 // the task of linking anagrams rather than a position
 
-	// make a list of words sharing each "change one letter" word variation
-	if runes < 1 {
-		runes = 3 * len(word)
+	// start workers
+	workers := minInt(s.widest+1, MaxProcs)
+	for i := 0; i < workers; i++ {
+		go func(id int, in chan int, out chan edgeList, s variants) {
+			var l edgeList
+			for pos := range in {
+				if pos == anagrams {
+					l.addAnagrams(word)
+					continue
+				}
+				s.find(pos)
+				s.each(l.addVariation)
+			}
+			out <- l
+		}(i, tasks, results, s)
 	}
-	link := make(map[[WIDEST]rune]Indexes, (10*runes+7)/8)
-	var key [WIDEST]rune
-	for wn, w := range word {
-		runes := []rune(w)
-		for i, r := range runes {
-			key[i] = r
-		}
-		for i, r := range runes {
-			key[i] = '?'
-			link[key] = append(link[key], Index(wn))
-			key[i] = r
+
+	// start dispatcher
+	go func(out chan int) {
+		if opt.Anagram {
+			out <- anagrams
 		}
-		for i := range runes {
-			key[i] = 0
+		for pos := 0; pos < s.widest; pos++ {
+			out <- pos
 		}
+		close(out)
+	}(tasks)
+
+	// harvest each worker's links
+	list := make([]edgeList, workers)
+	for i := range list {
+		list[i] = <-results
 	}
+	close(results)
 
-	pair := make([]Indexes, len(word))
-	for _, list := range link {
-		for _, wn1 := range list {
-			for _, wn2 := range list {
-				if wn1 != wn2 {
-					pair[wn1] = append(pair[wn1], wn2)
-				}
-			}
+	// count the links of each word so that the lists of all words share one array
+	// of pairs and one of tags, rather than growing separately
+	adj := adjacency{first: make(Indexes, len(word)+1)}
+	for _, l := range list {
+		for _, wn := range l.from {
+			adj.first[wn+1]++
 		}
 	}
-	for _, p := range pair {
-		sort.Sort(p) // keep ordered by word number
+	for wn := range word {
+		adj.first[wn+1] += adj.first[wn]
+	}
+	adj.link = make(Indexes, adj.first[len(word)])
+	tag := make([]Edge, adj.first[len(word)])
+	next := append(Indexes(nil), adj.first[:len(word)]...) // where each word's next link goes
+	for i, l := range list {
+		for j, wn := range l.from {
+			adj.link[next[wn]] = l.to[j]
+			tag[next[wn]] = l.tag[j]
+			next[wn]++
+		}
+		list[i] = edgeList{} // free for the sorting below
 	}
 
-	if verbose >= 1 {
-		total := 0
-		for _, v := range pair {
-			total += len(v)
+	// sorting makes the lists the same however the work was divided, and removing
+	// repeated links moves each list down to follow the last
+	n := 0
+	for wn := range word {
+		a, b := adj.first[wn], adj.first[wn+1]
+		l := links{adj.link[a:b], tag[a:b]}
+		sort.Sort(l) // keep ordered by word number
+		if opt.Indel {
+			l = l.unique() // repeated letters link by several gaps ("col" and "cool")
 		}
-		total /= 2 // undirected edges go both ways
+		adj.first[wn] = Index(n)
+		copy(tag[n:], l.tag)
+		n += copy(adj.link[n:], l.pair)
+	}
+	adj.first[len(word)] = Index(n)
+	adj.link, tag = adj.link[:n:n], tag[:n:n]
+
+	if opt.Verbose >= 1 {
+		total := len(adj.link) / 2 // undirected edges go both ways
 		density := float64(2*total) / float64(len(word)*(len(word)-1))
 		log.Printf("found %d edge%s between words (%.4f%% dense)\n", total, plural(total), 100*density)
 	}
-	if verbose >= 2 {
+	if opt.Verbose >= 2 {
 		fmt.Printf("linked words:\n")
 		for wn, w := range word {
-			if len(pair[wn]) > 0 { // not "aloof" as DEK would say
+			p, t := adj.of(Index(wn)), tag[adj.first[wn]:adj.first[wn+1]]
+			if len(p) > 0 { // not "aloof" as DEK would say
 				fmt.Printf("%5d: %-6s -> ", wn, w)
-				fmt.Printf("%s", word[pair[wn][0]])
-				for i := 1; i < len(pair[wn]); i++ {
-					fmt.Printf(", %s", word[pair[wn][i]])
+				fmt.Printf("%s (%v)", word[p[0]], t[0])
+				for i := 1; i < len(p); i++ {
+					fmt.Printf(", %s (%v)", word[p[i]], t[i])
 				}
 				fmt.Printf("\n")
 			}
 		}
 		fmt.Println()
 	}
-	return pair
+	return adj, tag
 
 }
 
@@ -348,7 +338,9 @@ func (c Components) Less(i, j int) bool {
 }
 
 // find connected components
-func findComponents(word []string, pair []Indexes) Components {
+func findComponents(word []string, adj adjacency, opt *Options) Components {
+	verbose := opt.Verbose
+
 	// every node has a corresponding component id
 	id := make([]Index, len(word))
 	for i := range id {
@@ -360,29 +352,29 @@ func findComponents(word []string, pair []Indexes) Components {
 	m := make(Indexes, 0, len(word))
 	var component Components
 
-	for w := range pair {
+	for w := Index(0); int(w) < adj.words(); w++ {
 		// find every active word reachable from this one
 		if id[w] == INFINITY {
 			switch {
-			case len(pair[w]) == 0: // disconnected word
+			case adj.degree(w) == 0: // disconnected word
 				id[w] = ids
-				m = append(m, Index(w))
-			case len(pair[w]) == 1 && len(pair[pair[w][0]]) == 1: // disconnected pair
-				w2 := pair[w][0]
+				m = append(m, w)
+			case adj.degree(w) == 1 && adj.degree(adj.of(w)[0]) == 1: // disconnected pair
+				w2 := adj.of(w)[0]
 				id[w] = ids
 				id[w2] = ids
-				m = append(m, Index(w), Index(w2))
+				m = append(m, w, w2)
 			default:
 				var head, tail int
-				id[w] = ids            // id of current component
-				queue[tail] = Index(w) // push starting word onto queue
+				id[w] = ids     // id of current component
+				queue[tail] = w // push starting word onto queue
 				tail++
-				m = append(m, Index(w))
+				m = append(m, w)
 
 				for head < tail { // breadth first traversal from w
 					n := queue[head]
 					head++
-					for _, wn := range pair[n] {
+					for _, wn := range adj.of(n) {
 						if id[wn] == INFINITY {
 							id[wn] = ids
 							queue[tail] = wn
@@ -439,15 +431,49 @@ func findComponents(word []string, pair []Indexes) Components {
 			}
 		}
 	}
+	if verbose >= 2 {
+		log.Printf("component articulation words and bridges:\n")
+		f := newCutFinder(len(word))
+		for cn, c := range component {
+			if c.words <= 2 {
+				break // components are sorted largest first
+			}
+			cuts := f.find(adj, c)
+			fmt.Printf("%4d: size = %4d, blocks = %4d, articulation words = %4d, bridges = %4d\n",
+				cn, c.words, cuts.Blocks, len(cuts.Articulation), len(cuts.Bridge))
+			var list []string
+			for _, wn := range cuts.Articulation {
+				list = append(list, word[wn])
+			}
+			printWords(list)
+			list = list[:0]
+			for _, b := range cuts.Bridge {
+				list = append(list, word[b[0]]+"-"+word[b[1]])
+			}
+			printWords(list)
+		}
+	}
 	return component
 }
 
-func sumAllSourcesShortestPathsV1(word []string, pair []Indexes, component []Component) (int, int) {
+// Sum the length of one shortest path between each pair of words. The results are the
+// number of word pairs, the number of paths (one per pair), and the summed lengths.
+func sumAllSourcesShortestPathsV1(word []string, adj adjacency, component []Component) (int, int, int) {
+	totalPairs, totalPaths, _ := histogramAllSourcesShortestPathsV1(word, adj, component)
+	return totalPairs, totalPairs, totalPaths
+}
+
+// Sum the length of one shortest path between each pair of words and count the pairs
+// at each distance. The results are the number of word pairs, the summed lengths, and
+// the histogram, whose element d is the number of ordered pairs at distance d.
+func histogramAllSourcesShortestPathsV1(word []string, adj adjacency, component []Component) (int, int, []int) {
 	var totalPairs, totalPaths int
+	var histogram []int
 	if len(component) > 0 {
-		distance := make([]Index, len(word))           // shortest distance to every node
-		queue := make([]Index, len(component[0].word)) // queue of newly processed fringe nodes
-		done := make([]bool, len(word))                // state: has node been processed?
+		distance := make([]Index, len(word))            // shortest distance to every node
+		queue := make([]Index, len(component[0].word))  // queue of newly processed fringe nodes
+		done := make([]bool, len(word))                 // state: has node been processed?
+		histogram = make([]int, len(component[0].word)) // distances are less than the words
 		// note: special cases for 1 and 2 words are optional speedups
 		for _, c := range component {
 			switch c.words {
@@ -455,28 +481,72 @@ func sumAllSourcesShortestPathsV1(word []string, pair []Indexes, component []Com
 			case 2:
 				totalPairs += 2
 				totalPaths += 2
+				histogram[1] += 2
 			default:
 				totalPairs += c.words * (c.words - 1)
 				for _, w := range c.word {
-					totalPaths += ssspBFS(c.word, pair, Index(w), distance, queue, done)
+					sum, _ := ssspBFS(c.word, adj, Index(w), distance, queue, done, histogram)
+					totalPaths += sum
 				}
 			}
 		}
 	}
-	return totalPairs, totalPaths
+	return totalPairs, totalPaths, trimHistogram(histogram)
 }
 
 // Compute Single Source Shortest Paths (SSSP) between a single source node and all
-// other nodes of the connected component. Return distance array in d and the sum
-// of the lengths of the shortest paths. Uses simple Breadth First Search which is
+// other nodes of the connected component. Return distance array in d, the sum of
+// the lengths of the shortest paths, and the greatest of those lengths, which is the
+// eccentricity of the source node. When histogram is not nil, add the number of nodes
+// found at each distance d to histogram[d]. Uses simple Breadth First Search which is
 // an optimal foundation for SSSP/ASSP in unweighted adjacency-list graphs. BFS is
 // friendly to parallelism since is has no impediment to concurrent evaluation.
-func ssspBFS(word []Index, pair []Indexes, w Index, distance, queue []Index, done []bool) int {
+func ssspBFS(word []Index, adj adjacency, w Index, distance, queue []Index, done []bool, histogram []int) (int, int) {
+	for _, wn := range word {
+		distance[wn] = INFINITY // not known to be rechable (can be graph or component)
+		done[wn] = false        // not yet discovered and processed by traversal
+	}
+	distance[w] = 0
+	done[w] = true
+
+	// push starting word onto queue
+	var head, tail int
+	queue[tail] = w
+	tail++
+
+	// breadth first traversal of graph rooted at w
+	total := 0
+	for head < tail { // while queue is not empty
+		n := queue[head]
+		head++
+		d := distance[n] + 1
+		found := tail
+		for _, wn := range adj.link[adj.first[n]:adj.first[n+1]] {
+			if !done[wn] {
+				done[wn] = true
+				distance[wn] = d
+				queue[tail] = wn
+				tail++
+				total += int(d)
+			}
+		}
+		if histogram != nil && tail > found {
+			histogram[d] += tail - found // once per node rather than per edge
+		}
+	}
+	return total, int(distance[queue[tail-1]]) // the last node queued is the farthest
+}
+
+// Variant of ssspBFS that also records the predecessor of each node discovered by
+// the traversal in parent, so that a shortest path from w to any node n in the
+// component can be recovered by following parent links from n back to w.
+func ssspBFSParent(word []Index, adj adjacency, w Index, distance, parent, queue []Index, done []bool) int {
 	for _, wn := range word {
 		distance[wn] = INFINITY // not known to be rechable (can be graph or component)
 		done[wn] = false        // not yet discovered and processed by traversal
 	}
 	distance[w] = 0
+	parent[w] = w
 	done[w] = true
 
 	// push starting word onto queue
@@ -490,10 +560,11 @@ func ssspBFS(word []Index, pair []Indexes, w Index, distance, queue []Index, don
 		n := queue[head]
 		head++
 		d := distance[n] + 1
-		for _, wn := range pair[n] {
+		for _, wn := range adj.link[adj.first[n]:adj.first[n+1]] {
 			if !done[wn] {
 				done[wn] = true
 				distance[wn] = d
+				parent[wn] = n
 				queue[tail] = wn
 				tail++
 				total += int(d)
@@ -506,29 +577,38 @@ func ssspBFS(word []Index, pair []Indexes, w Index, distance, queue []Index, don
 // ideally the breakpoint would be determined by a test
 const BREAKPOINT = 16 // switch from internal to external parallelism
 
-func sumAllSourcesShortestPathsV2(word []string, pair []Indexes, component []Component) (int, int) {
+func sumAllSourcesShortestPathsV2(word []string, adj adjacency, component []Component) (int, int, int) {
+	totalPairs, totalPaths, _ := histogramAllSourcesShortestPathsV2(word, adj, component)
+	return totalPairs, totalPairs, totalPaths
+}
+
+func histogramAllSourcesShortestPathsV2(word []string, adj adjacency, component []Component) (int, int, []int) {
 	var i, j, totalPairs, totalPaths int
 	components := len(component)
 
 	// optimization -- skip parallel framework overhead when nothing but simple tasks
 	if true {
 		if components > 0 && component[0].words <= 16 {
-			return sumAllSourcesShortestPathsV1(word, pair, component)
+			return histogramAllSourcesShortestPathsV1(word, adj, component)
 		}
 	}
+	var histogram []int
+	if components > 0 {
+		histogram = make([]int, component[0].words) // distances are less than the words
+	}
 
 	// solve large problems sequentially, using parallel workers within each
 	for ; i < components && component[i].words >= BREAKPOINT; i++ {
 		c := component[i]
 		totalPairs += c.words * (c.words - 1)
-		totalPaths += ssspWordsParallel(word, pair, c)
+		totalPaths += ssspWordsParallel(adj, c, histogram)
 	}
 
 	// solve medium problems in parallel, using a single worker for each
 	for j = i; j < components && component[j].words > 2; j++ {
 	}
 	if i < j {
-		pairCount, pathCount := ssspComponentsParallel(word, pair, component[i:j])
+		pairCount, pathCount := ssspComponentsParallel(adj, component[i:j], histogram)
 		totalPairs += pairCount
 		totalPaths += pathCount
 		i = j
@@ -542,24 +622,28 @@ func sumAllSourcesShortestPathsV2(word []string, pair []Indexes, component []Com
 		case c.words == 2: // single pair of words with two length 1 solutions (a->b and b->a)
 			totalPairs += 2
 			totalPaths += 2
+			histogram[1] += 2
 		default:
-			log.Fatal("internal error: small problem with more than 2 nodes")
+			panic("internal error: small problem with more than 2 nodes")
 		}
 	}
-	return totalPairs, totalPaths
+	return totalPairs, totalPaths, trimHistogram(histogram)
 }
 
-func ssspComponentsParallel(word []string, pair []Indexes, component []Component) (int, int) {
+// Each worker counts distances in its own histogram, merged into histogram at the end.
+func ssspComponentsParallel(adj adjacency, component []Component, histogram []int) (int, int) {
 	var totalPairs, totalPaths int
 	tasks := make(chan Component) //, 1024)
 	results := make(chan int)     //, 1024)
+	histograms := make(chan []int)
 
 	// start workers
 	workers := MaxProcs
 	for k := 0; k < workers; k++ {
-		go func(id int, in chan Component, out chan int, word []string, pair []Indexes) {
-			distance := make(Indexes, len(word))
-			done := make([]bool, len(word))
+		go func(id int, in chan Component, out chan int, adj adjacency) {
+			distance := make(Indexes, adj.words())
+			done := make([]bool, adj.words())
+			count := make([]int, component[0].words) // components are sorted largest first
 			var queue Indexes
 
 			for c := range in {
@@ -569,9 +653,10 @@ func ssspComponentsParallel(word []string, pair []Indexes, component []Component
 					queue = queue[:c.words]
 				}
 
-				out <- ssspWordsSerial(word, pair, c, distance, queue, done)
+				out <- ssspWordsSerial(adj, c, distance, queue, done, count)
 			}
-		}(k, tasks, results, word, pair)
+			histograms <- count
+		}(k, tasks, results, adj)
 	}
 
 	// dispatch tasks to workers
@@ -587,6 +672,9 @@ func ssspComponentsParallel(word []string, pair []Indexes, component []Component
 		totalPaths += <-results
 	}
 	close(results)
+	for k := 0; k < workers; k++ {
+		addHistogram(histogram, <-histograms)
+	}
 
 	// determine number of pairs for these components
 	for _, c := range component {
@@ -595,21 +683,26 @@ func ssspComponentsParallel(word []string, pair []Indexes, component []Component
 	return totalPairs, totalPaths
 }
 
-func ssspWordsParallel(word []string, pair []Indexes, c Component) int {
+// Each worker counts distances in its own histogram, merged into histogram at the end.
+func ssspWordsParallel(adj adjacency, c Component, histogram []int) int {
 	tasks := make(chan Index) //, 1024)
 	results := make(chan int) //, 1024)
+	histograms := make(chan []int)
 
 	// start workers
 	workers := minInt(c.words, MaxProcs)
 	for i := 0; i < workers; i++ {
-		go func(id int, in chan Index, out chan int, word []string, pair []Indexes, c Component) {
-			distance := make(Indexes, len(word))
+		go func(id int, in chan Index, out chan int, adj adjacency, c Component) {
+			distance := make(Indexes, adj.words())
 			queue := make(Indexes, len(c.word))
-			done := make([]bool, len(word))
+			done := make([]bool, adj.words())
+			count := make([]int, c.words)
 			for w := range in {
-				out <- ssspBFS(c.word, pair, Index(w), distance, queue, done)
+				sum, _ := ssspBFS(c.word, adj, Index(w), distance, queue, done, count)
+				out <- sum
 			}
-		}(i, tasks, results, word, pair, c)
+			histograms <- count
+		}(i, tasks, results, adj, c)
 	}
 
 	// start dispatcher
@@ -626,17 +719,456 @@ func ssspWordsParallel(word []string, pair []Indexes, c Component) int {
 		total += <-results
 	}
 	close(results)
+	for i := 0; i < workers; i++ {
+		addHistogram(histogram, <-histograms)
+	}
 	return total
 }
 
-func ssspWordsSerial(word []string, pair []Indexes, c Component, distance, queue []Index, done []bool) int {
+func ssspWordsSerial(adj adjacency, c Component, distance, queue []Index, done []bool, histogram []int) int {
 	total := 0
 	for _, w := range c.word {
-		total += ssspBFS(c.word, pair, w, distance, queue, done)
+		sum, _ := ssspBFS(c.word, adj, w, distance, queue, done, histogram)
+		total += sum
 	}
 	return total
 }
 
+// add the counts of histogram b to those of a, which is at least as long
+func addHistogram(a, b []int) {
+	for d, n := range b {
+		a[d] += n
+	}
+}
+
+// drop the zero counts beyond the greatest distance found
+func trimHistogram(histogram []int) []int {
+	n := len(histogram)
+	for n > 0 && histogram[n-1] == 0 {
+		n--
+	}
+	return histogram[:n]
+}
+
+//
+// all shortest paths: count every shortest path between each pair, not just one
+//
+
+// Sum the lengths of all shortest paths between each pair of words. The results are
+// the number of word pairs, the number of distinct shortest paths, and their summed
+// lengths. The number of shortest paths between two words can grow exponentially
+// with their distance, so the totals are big integers, and the paths from any word
+// whose counts would overflow 64 bits are counted again in big integers.
+func sumAllSourcesAllShortestPathsV1(word []string, adj adjacency, component []Component) (int, *big.Int, *big.Int) {
+	var totalPairs int
+	var total pathSum
+	if len(component) > 0 {
+		p := newPathCounter(adj.words(), component[0].words)
+		for _, c := range component {
+			switch c.words {
+			case 1:
+			case 2:
+				totalPairs += 2
+				total.add(2, 2)
+			default:
+				totalPairs += c.words * (c.words - 1)
+				for _, w := range c.word {
+					p.sumFrom(c.word, adj, w, &total)
+				}
+			}
+		}
+	}
+	return totalPairs, &total.paths, &total.lengths
+}
+
+// number of shortest paths and their summed lengths
+type pathSum struct {
+	paths, lengths big.Int
+}
+
+// add paths shortest paths of summed length lengths
+func (s *pathSum) add(paths, lengths uint64) {
+	var n big.Int
+	s.paths.Add(&s.paths, n.SetUint64(paths))
+	s.lengths.Add(&s.lengths, n.SetUint64(lengths))
+}
+
+// add the paths and lengths of another sum
+func (s *pathSum) addSum(t *pathSum) {
+	s.paths.Add(&s.paths, &t.paths)
+	s.lengths.Add(&s.lengths, &t.lengths)
+}
+
+// Scratch space for counting the shortest paths from one word at a time, in uint64
+// or, from words where that would overflow, in big integers allocated on first use.
+type pathCounter struct {
+	distance Indexes
+	queue    Indexes
+	done     []bool
+	count    []uint64
+	bigCount []big.Int
+}
+
+// Prepare to count paths among words numbered below words, in components of at
+// most queue words.
+func newPathCounter(words, queue int) *pathCounter {
+	return &pathCounter{
+		distance: make(Indexes, words),
+		queue:    make(Indexes, queue),
+		done:     make([]bool, words),
+		count:    make([]uint64, words),
+	}
+}
+
+// Add the number of shortest paths from w to the other words of its component, and
+// their summed lengths, to sum.
+func (p *pathCounter) sumFrom(word []Index, adj adjacency, w Index, sum *pathSum) {
+	if paths, lengths, ok := ssspBFSAll(word, adj, w, p.distance, p.queue, p.done, p.count); ok {
+		sum.add(paths, lengths)
+		return
+	}
+	if p.bigCount == nil {
+		p.bigCount = make([]big.Int, len(p.count))
+	}
+	ssspBFSAllBig(word, adj, w, p.distance, p.queue, p.done, p.bigCount, sum)
+}
+
+// Variant of ssspBFS that counts all of the shortest paths from w to each node of the
+// component. Every node discovered at distance d+1 from a node at distance d extends
+// each of that node's shortest paths by one step, so counts accumulate level by level
+// in the same traversal. Returns the number of shortest paths from w to other nodes
+// and the sum of their lengths, and false if either or any count overflowed.
+func ssspBFSAll(word []Index, adj adjacency, w Index, distance, queue []Index, done []bool, count []uint64) (uint64, uint64, bool) {
+	for _, wn := range word {
+		distance[wn] = INFINITY // not known to be rechable (can be graph or component)
+		done[wn] = false        // not yet discovered and processed by traversal
+		count[wn] = 0           // no paths known yet
+	}
+	distance[w] = 0
+	done[w] = true
+	count[w] = 1
+
+	// push starting word onto queue
+	var head, tail int
+	queue[tail] = w
+	tail++
+
+	var carry uint64 // nonzero once any count or sum overflows
+
+	// breadth first traversal of graph rooted at w
+	for head < tail { // while queue is not empty
+		n := queue[head]
+		head++
+		d := distance[n] + 1
+		for _, wn := range adj.link[adj.first[n]:adj.first[n+1]] {
+			switch {
+			case !done[wn]:
+				done[wn] = true
+				distance[wn] = d
+				count[wn] = count[n]
+				queue[tail] = wn
+				tail++
+			case distance[wn] == d: // another shortest path to wn
+				var c uint64
+				count[wn], c = bits.Add64(count[wn], count[n], 0)
+				carry |= c
+			}
+		}
+	}
+
+	// counts are final once traversal completes
+	var paths, total uint64
+	for _, wn := range queue[1:tail] {
+		hi, length := bits.Mul64(count[wn], uint64(distance[wn]))
+		var c1, c2 uint64
+		paths, c1 = bits.Add64(paths, count[wn], 0)
+		total, c2 = bits.Add64(total, length, 0)
+		carry |= hi | c1 | c2
+	}
+	return paths, total, carry == 0
+}
+
+// Version of ssspBFSAll that counts in big integers, for words with more shortest
+// paths than 64 bits can count, adding the paths and their lengths to sum.
+func ssspBFSAllBig(word []Index, adj adjacency, w Index, distance, queue []Index, done []bool, count []big.Int, sum *pathSum) {
+	for _, wn := range word {
+		distance[wn] = INFINITY
+		done[wn] = false
+		count[wn].SetInt64(0)
+	}
+	distance[w] = 0
+	done[w] = true
+	count[w].SetInt64(1)
+
+	var head, tail int
+	queue[tail] = w
+	tail++
+
+	for head < tail {
+		n := queue[head]
+		head++
+		d := distance[n] + 1
+		for _, wn := range adj.link[adj.first[n]:adj.first[n+1]] {
+			switch {
+			case !done[wn]:
+				done[wn] = true
+				distance[wn] = d
+				count[wn].Set(&count[n])
+				queue[tail] = wn
+				tail++
+			case distance[wn] == d:
+				count[wn].Add(&count[wn], &count[n])
+			}
+		}
+	}
+
+	var length big.Int
+	for _, wn := range queue[1:tail] {
+		sum.paths.Add(&sum.paths, &count[wn])
+		sum.lengths.Add(&sum.lengths, length.Mul(&count[wn], length.SetUint64(uint64(distance[wn]))))
+	}
+}
+
+func sumAllSourcesAllShortestPathsV2(word []string, adj adjacency, component []Component) (int, *big.Int, *big.Int) {
+	var i, j, totalPairs int
+	var total pathSum
+	components := len(component)
+
+	// optimization -- skip parallel framework overhead when nothing but simple tasks
+	if components > 0 && component[0].words <= 16 {
+		return sumAllSourcesAllShortestPathsV1(word, adj, component)
+	}
+
+	// solve large problems sequentially, using parallel workers within each
+	for ; i < components && component[i].words >= BREAKPOINT; i++ {
+		c := component[i]
+		totalPairs += c.words * (c.words - 1)
+		total.addSum(ssspAllWordsParallel(adj, c))
+	}
+
+	// solve medium problems in parallel, using a single worker for each
+	for j = i; j < components && component[j].words > 2; j++ {
+	}
+	if i < j {
+		pairCount, sum := ssspAllComponentsParallel(adj, component[i:j])
+		totalPairs += pairCount
+		total.addSum(sum)
+		i = j
+	}
+
+	// solve small (nodes <= 2) problems directly
+	for ; i < len(component); i++ {
+		c := component[i]
+		switch {
+		case c.words == 1: // single aloof word with no solutions
+		case c.words == 2: // single pair of words with two length 1 solutions (a->b and b->a)
+			totalPairs += 2
+			total.add(2, 2)
+		default:
+			panic("internal error: small problem with more than 2 nodes")
+		}
+	}
+	return totalPairs, &total.paths, &total.lengths
+}
+
+func ssspAllComponentsParallel(adj adjacency, component []Component) (int, *pathSum) {
+	var totalPairs int
+	var total pathSum
+	tasks := make(chan Component)
+	results := make(chan *pathSum)
+
+	// start workers
+	workers := MaxProcs
+	for k := 0; k < workers; k++ {
+		go func(id int, in chan Component, out chan *pathSum, adj adjacency) {
+			p := newPathCounter(adj.words(), component[0].words) // components are sorted largest first
+			for c := range in {
+				sum := new(pathSum)
+				for _, w := range c.word {
+					p.sumFrom(c.word, adj, w, sum)
+				}
+				out <- sum
+			}
+		}(k, tasks, results, adj)
+	}
+
+	// dispatch tasks to workers
+	go func(out chan Component, component []Component) {
+		for _, c := range component {
+			out <- c
+		}
+		close(out)
+	}(tasks, component)
+
+	// harvest results from workers
+	for _ = range component {
+		total.addSum(<-results)
+	}
+	close(results)
+
+	// determine number of pairs for these components
+	for _, c := range component {
+		totalPairs += c.words * (c.words - 1)
+	}
+	return totalPairs, &total
+}
+
+func ssspAllWordsParallel(adj adjacency, c Component) *pathSum {
+	tasks := make(chan Index)
+	results := make(chan *pathSum)
+
+	// start workers
+	workers := minInt(c.words, MaxProcs)
+	for i := 0; i < workers; i++ {
+		go func(id int, in chan Index, out chan *pathSum, adj adjacency, c Component) {
+			p := newPathCounter(adj.words(), c.words)
+			for w := range in {
+				sum := new(pathSum)
+				p.sumFrom(c.word, adj, w, sum)
+				out <- sum
+			}
+		}(i, tasks, results, adj, c)
+	}
+
+	// start dispatcher
+	go func(out chan Index, c Component) {
+		for _, w := range c.word {
+			out <- w
+		}
+		close(out)
+	}(tasks, c)
+
+	// harvest results from workers
+	var total pathSum
+	for _ = range c.word {
+		total.addSum(<-results)
+	}
+	close(results)
+	return &total
+}
+
+//
+// Doublet solver
+//
+
+// Find one shortest ladder from word w1 to word w2, both in component c. The result
+// lists the word numbers along the ladder, starting with w1 and ending with w2.
+func findLadder(adj adjacency, c Component, w1, w2 Index) Indexes {
+	distance := make([]Index, adj.words())
+	parent := make([]Index, adj.words())
+	queue := make([]Index, c.words)
+	done := make([]bool, adj.words())
+	ssspBFSParent(c.word, adj, w1, distance, parent, queue, done)
+	if !done[w2] {
+		return nil // not reachable (should not happen within a component)
+	}
+
+	// follow parent links from w2 back to w1
+	ladder := make(Indexes, distance[w2]+1)
+	for i, w := len(ladder)-1, w2; i >= 0; i-- {
+		ladder[i] = w
+		w = parent[w]
+	}
+	return ladder
+}
+
+// Count the distinct shortest ladders from word w1 to word w2, both in component c.
+// A BFS rooted at w2 layers the component by distance; the shortest ladders are the
+// paths that step from each layer to the next nearer one, and the number of them
+// reaching each word is the sum over its neighbors one step farther from w2. These
+// counts grow exponentially with ladder length in dense graphs, so use big integers.
+func countLadders(adj adjacency, c Component, w1, w2 Index) *big.Int {
+	distance := make([]Index, adj.words())
+	queue := make([]Index, c.words)
+	done := make([]bool, adj.words())
+	ssspBFS(c.word, adj, w2, distance, queue, done, nil)
+	if !done[w1] {
+		return new(big.Int) // not reachable (should not happen within a component)
+	}
+
+	// visit words in order of decreasing distance from w2, starting at w1
+	count := make(map[Index]*big.Int)
+	count[w1] = big.NewInt(1)
+	for i := c.words - 1; i >= 0; i-- {
+		n := queue[i]
+		cn, ok := count[n]
+		if !ok {
+			continue // not on any shortest ladder from w1
+		}
+		if n == w2 {
+			return cn
+		}
+		for _, wn := range adj.of(n) {
+			if distance[wn] == distance[n]-1 {
+				if count[wn] == nil {
+					count[wn] = new(big.Int)
+				}
+				count[wn].Add(count[wn], cn)
+			}
+		}
+	}
+	return new(big.Int)
+}
+
+// List the distinct shortest ladders from word w1 to word w2, both in component c,
+// in alphabetical order and stopping after limit ladders (zero means no limit).
+func listLadders(adj adjacency, c Component, w1, w2 Index, limit int) []Indexes {
+	distance := make([]Index, adj.words())
+	queue := make([]Index, c.words)
+	done := make([]bool, adj.words())
+	ssspBFS(c.word, adj, w2, distance, queue, done, nil)
+	if !done[w1] {
+		return nil // not reachable (should not happen within a component)
+	}
+
+	// every step to a word one nearer to w2 extends a shortest ladder; there are no
+	// dead ends since each such word has a neighbor nearer still (w2 itself at 0)
+	var ladders []Indexes
+	steps := int(distance[w1])
+	ladder := make(Indexes, steps+1)
+	var walk func(w Index) bool
+	walk = func(w Index) bool {
+		d := distance[w]
+		ladder[steps-int(d)] = w
+		if d == 0 {
+			ladders = append(ladders, append(Indexes(nil), ladder...))
+			return limit <= 0 || len(ladders) < limit
+		}
+		for _, wn := range adj.of(w) {
+			if distance[wn] == d-1 && !walk(wn) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(w1)
+	return ladders
+}
+
+// Find the word number of a word in the ordered word list, ignoring case unless the
+// list (perhaps the node names of an imported graph) has the word as given.
+func findWord(word []string, s string, normalizer *Normalizer) (Index, bool) {
+	for _, t := range []string{s, normalizer.Word(s)} {
+		i := sort.SearchStrings(word, t)
+		if i < len(word) && word[i] == t {
+			return Index(i), true
+		}
+	}
+	return 0, false
+}
+
+// Find the component containing word w (or -1 when there is none). Each
+// component's word list is ordered by word number so a binary search suffices.
+func componentOf(component Components, w Index) int {
+	for cn, c := range component {
+		i := sort.Search(len(c.word), func(i int) bool { return c.word[i] >= w })
+		if i < len(c.word) && c.word[i] == w {
+			return cn
+		}
+	}
+	return -1
+}
+
 //
 // utility functions
 //
@@ -667,25 +1199,11 @@ func widestString(word []string) int {
 	return widest
 }
 
-func writeWords(word []string, filename string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+type runeSlice []rune
 
-	w := bufio.NewWriter(file)
-	for _, s := range word {
-		w.WriteString(s)
-		w.WriteString("\n")
-	}
-	w.Flush()
-
-	if verbose >= 1 {
-		log.Printf("wrote %v words to file %v", len(word), filename)
-	}
-	return nil
-}
+func (a runeSlice) Len() int           { return len(a) }
+func (a runeSlice) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a runeSlice) Less(i, j int) bool { return a[i] < a[j] }
 
 func plural(n int) string {
 	if n == 1 {
@@ -701,6 +1219,13 @@ func maxInt(a, b int) int {
 	return b
 }
 
+func absInt(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
 func minInt(a, b int) int {
 	if a <= b {
 		return a
@@ -714,73 +1239,3 @@ func minIndex(a, b Index) Index {
 	}
 	return b
 }
-
-//
-// simple performance meter: instantiate one and print it first on output lines.
-// set work to display activities per second (bytes, pages, queries, words etc.)
-// run this program with "-t" to see it in action. It is helpful to see elapsed
-// times and also effective degree of parallelism across various parts of code.
-//
-
-type Meter struct {
-	now     time.Time
-	user    float64
-	system  float64
-	memory  uint64
-	elapsed float64
-	dUser   float64
-	dSystem float64
-	dMemory uint64
-	work    float64
-}
-
-func NewMeter() *Meter {
-	m := &Meter{now: time.Now()}
-	m.user, m.system, m.memory = ProcessTimes()
-	return m
-}
-
-func ProcessTimes() (user, system float64, size uint64) {
-	var usage syscall.Rusage
-	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
-		fmt.Printf("Error: unable to gather resource usage data: %v\n", err)
-	}
-	user = float64(usage.Utime.Sec) + float64(usage.Utime.Usec)/1e6
-	system = float64(usage.Stime.Sec) + float64(usage.Stime.Usec)/1e6
-	size = uint64(uint32(usage.Maxrss))
-	return
-}
-
-func (m *Meter) SetWork(work float64) {
-	m.work = work
-}
-
-func (m *Meter) String() string {
-	now := time.Now()
-	user, system, memory := ProcessTimes()
-
-	elapsed := float64((now.Sub(m.now))) / 1e9
-	dUser := user - m.user
-	dSystem := system - m.system
-	dMemory := memory - m.memory
-
-	var s string
-	if m.work > 0 && dUser >= 0.0001 {
-		s = fmt.Sprintf("%12.6f (%10.3f+%9.3f) %7.3f%% %9.3f MiB %9.0f/sec (%11.0f/sec)",
-			elapsed, dUser, dSystem, 100*(dUser+dSystem)/elapsed, float64(dMemory)/(1024.0*1024.0), m.work/dUser, m.work/elapsed)
-	} else {
-		s = fmt.Sprintf("%12.6f (%10.3f+%9.3f) %7.3f%% %9.3f MiB                                ",
-			elapsed, dUser, dSystem, 100*(dUser+dSystem)/elapsed, float64(dMemory)/(1024.0*1024.0))
-	}
-
-	m.now = now
-	m.user = user
-	m.system = system
-	m.memory = memory
-	m.elapsed = elapsed
-	m.dUser = dUser
-	m.dSystem = dSystem
-	m.dMemory = dMemory
-
-	return s
-}
@@ -1,6 +1,10 @@
-package main
+package ladder
 
-import "testing"
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
 
 func buildGraph(n int) ([]string, []Component) {
 	node := make([]string, n)
@@ -17,7 +21,26 @@ func buildGraph(n int) ([]string, []Component) {
 	return node, component
 }
 
-type Summer func(word []string, pair []Indexes, component []Component) (int, int, int)
+type Summer func(word []string, adj adjacency, component []Component) (int, int, int)
+
+// the graph of the lists built below as the summers search it, with the words of
+// each component numbered together
+func searched(node []string, a []Indexes, component []Component) ([]string, adjacency, []Component) {
+	adj, component := relabel(newAdjacency(a), component)
+	return node, adj, component
+}
+
+// adapt a summer of all shortest paths, which counts them in big integers, to the
+// tables below, where counts fit in an int (or are reported as -1)
+func allPaths(summer func([]string, adjacency, []Component) (int, *big.Int, *big.Int)) Summer {
+	return func(word []string, adj adjacency, component []Component) (int, int, int) {
+		pairs, paths, lengths := summer(word, adj, component)
+		if !paths.IsInt64() || !lengths.IsInt64() {
+			return pairs, -1, -1
+		}
+		return pairs, int(paths.Int64()), int(lengths.Int64())
+	}
+}
 
 // Path graph, P_n
 // http://en.wikipedia.org/wiki/Path_graph
@@ -35,6 +58,7 @@ func buildPathGraph(n int) ([]string, []Indexes, []Component) {
 	a := make([]Indexes, n)
 	for i := range a {
 		switch {
+		case n == 1: // a single node without links
 		case i == 0:
 			a[i] = []Index{Index(i + 1)}
 		case i < n-1:
@@ -75,7 +99,7 @@ func testPathGraph(t *testing.T, summer Summer) {
 		sum := (n * (n*n - 1)) / 3
 
 		node, a, component := buildPathGraph(n)
-		pairs2, paths2, sum2 := summer(node, a, component)
+		pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 		if pairs != pairs2 || paths != paths2 || sum != sum2 {
 			t.Errorf("%2d: expected (%d, %d, %d), computed (%d, %d, %d)",
@@ -92,16 +116,20 @@ func TestPathGraphOneV2(t *testing.T) {
 	testPathGraph(t, sumAllSourcesShortestPathsV2)
 }
 
+func TestPathGraphOneV3(t *testing.T) {
+	testPathGraph(t, sumAllSourcesShortestPathsV3)
+}
+
 //
 // same values as table above for the All version
 //
 
 func TestPathGraphAllV1(t *testing.T) {
-	testPathGraph(t, sumAllSourcesAllShortestPathsV1)
+	testPathGraph(t, allPaths(sumAllSourcesAllShortestPathsV1))
 }
 
 func TestPathGraphAllV2(t *testing.T) {
-	testPathGraph(t, sumAllSourcesAllShortestPathsV2)
+	testPathGraph(t, allPaths(sumAllSourcesAllShortestPathsV2))
 }
 
 // Complete graph, K_n
@@ -165,7 +193,7 @@ func testCompleteGraph(t *testing.T, summer Summer) {
 		sum := pairs
 
 		node, a, component := buildCompleteGraph(n)
-		pairs2, paths2, sum2 := summer(node, a, component)
+		pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 		if pairs != pairs2 || paths != paths2 || sum != sum2 {
 			t.Errorf("%2d: expected (%d, %d, %d), computed (%d, %d, %d)",
@@ -182,16 +210,20 @@ func TestCompleteGraphOneV2(t *testing.T) {
 	testCompleteGraph(t, sumAllSourcesShortestPathsV2)
 }
 
+func TestCompleteGraphOneV3(t *testing.T) {
+	testCompleteGraph(t, sumAllSourcesShortestPathsV3)
+}
+
 //
 // same values as table above for the All version
 //
 
 func TestCompleteGraphAllV1(t *testing.T) {
-	testCompleteGraph(t, sumAllSourcesAllShortestPathsV1)
+	testCompleteGraph(t, allPaths(sumAllSourcesAllShortestPathsV1))
 }
 
 func TestCompleteGraphAllV2(t *testing.T) {
-	testCompleteGraph(t, sumAllSourcesAllShortestPathsV2)
+	testCompleteGraph(t, allPaths(sumAllSourcesAllShortestPathsV2))
 }
 
 // Star graph, S_n
@@ -254,7 +286,7 @@ func testStarGraph(t *testing.T, summer Summer) {
 		sum := 2 * (n - 1) * (n - 1)
 
 		node, a, component := buildStarGraph(n)
-		pairs2, paths2, sum2 := summer(node, a, component)
+		pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 		if pairs != pairs2 || paths != paths2 || sum != sum2 {
 			t.Errorf("%2d: expected (%d, %d, %d), computed (%d, %d, %d)",
@@ -271,16 +303,20 @@ func TestStarGraphOneV2(t *testing.T) {
 	testStarGraph(t, sumAllSourcesShortestPathsV2)
 }
 
+func TestStarGraphOneV3(t *testing.T) {
+	testStarGraph(t, sumAllSourcesShortestPathsV3)
+}
+
 //
 // same values as table above for the All version
 //
 
 func TestStarGraphAllV1(t *testing.T) {
-	testStarGraph(t, sumAllSourcesAllShortestPathsV1)
+	testStarGraph(t, allPaths(sumAllSourcesAllShortestPathsV1))
 }
 
 func TestStarGraphAllV2(t *testing.T) {
-	testStarGraph(t, sumAllSourcesAllShortestPathsV2)
+	testStarGraph(t, allPaths(sumAllSourcesAllShortestPathsV2))
 }
 
 // Complete binary tree, T_n (full, same height, all leaves full)
@@ -339,7 +375,7 @@ func testBinaryTree(t *testing.T, summer Summer) {
 		sum := 2 * p * ((n-2)*(p+1) + 6)
 
 		node, a, component := buildCompleteBinaryTree(n)
-		pairs2, paths2, sum2 := summer(node, a, component)
+		pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 		if pairs != pairs2 || paths != paths2 || sum != sum2 {
 			t.Errorf("%2d: expected (%12d, %12d, %12d), computed (%12d, %12d, %12d)",
@@ -356,16 +392,20 @@ func TestBinaryTreeOneV2(t *testing.T) {
 	testBinaryTree(t, sumAllSourcesShortestPathsV2)
 }
 
+func TestBinaryTreeOneV3(t *testing.T) {
+	testBinaryTree(t, sumAllSourcesShortestPathsV3)
+}
+
 //
 // same values as table above for the All version
 //
 
 func TestBinaryTreeAllV1(t *testing.T) {
-	testBinaryTree(t, sumAllSourcesAllShortestPathsV1)
+	testBinaryTree(t, allPaths(sumAllSourcesAllShortestPathsV1))
 }
 
 func TestBinaryTreeAllV2(t *testing.T) {
-	testBinaryTree(t, sumAllSourcesAllShortestPathsV2)
+	testBinaryTree(t, allPaths(sumAllSourcesAllShortestPathsV2))
 }
 
 // Cycle graph, C_n
@@ -427,7 +467,7 @@ func testCycleGraphOne(t *testing.T, summer Summer) {
 		}
 
 		node, a, component := buildCycleGraph(n)
-		pairs2, paths2, sum2 := summer(node, a, component)
+		pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 		if pairs != pairs2 || paths != paths2 || sum != sum2 {
 			t.Errorf("%2d: expected (%d, %d, %d), computed (%d, %d, %d)",
@@ -444,6 +484,10 @@ func TestCycleGraphOneV2(t *testing.T) {
 	testCycleGraphOne(t, sumAllSourcesShortestPathsV2)
 }
 
+func TestCycleGraphOneV3(t *testing.T) {
+	testCycleGraphOne(t, sumAllSourcesShortestPathsV3)
+}
+
 // Cycle graph all
 // Sums of all shortest-length paths between each pair
 // Parameterized by n, the number of nodes
@@ -480,7 +524,7 @@ func testCycleGraphAll(t *testing.T, summer Summer) {
 		}
 
 		node, a, component := buildCycleGraph(n)
-		pairs2, paths2, sum2 := summer(node, a, component)
+		pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 		if pairs != pairs2 || paths != paths2 || sum != sum2 {
 			t.Errorf("%2d: expected (%d, %d, %d), computed (%d, %d, %d)",
@@ -490,11 +534,11 @@ func testCycleGraphAll(t *testing.T, summer Summer) {
 }
 
 func TestCycleGraphAllV1(t *testing.T) {
-	testCycleGraphAll(t, sumAllSourcesAllShortestPathsV1)
+	testCycleGraphAll(t, allPaths(sumAllSourcesAllShortestPathsV1))
 }
 
 func TestCycleGraphAllV2(t *testing.T) {
-	testCycleGraphAll(t, sumAllSourcesAllShortestPathsV2)
+	testCycleGraphAll(t, allPaths(sumAllSourcesAllShortestPathsV2))
 }
 
 // Wheel graph, W_n
@@ -561,7 +605,7 @@ func testWheelGraphOne(t *testing.T, summer Summer) {
 		sum := 2 * (n - 1) * (n - 2)
 
 		node, a, component := buildWheelGraph(n)
-		pairs2, paths2, sum2 := summer(node, a, component)
+		pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 		if pairs != pairs2 || paths != paths2 || sum != sum2 {
 			t.Errorf("%2d: expected (%d, %d, %d), computed (%d, %d, %d)",
@@ -578,6 +622,10 @@ func TestWheelGraphOneV2(t *testing.T) {
 	testWheelGraphOne(t, sumAllSourcesShortestPathsV2)
 }
 
+func TestWheelGraphOneV3(t *testing.T) {
+	testWheelGraphOne(t, sumAllSourcesShortestPathsV3)
+}
+
 // Wheel graph all
 // Counting all shortest paths between each pair
 // Parameterized by n, the number of nodes
@@ -612,7 +660,7 @@ func testWheelGraphAll(t *testing.T, summer Summer) {
 		}
 
 		node, a, component := buildWheelGraph(n)
-		pairs2, paths2, sum2 := summer(node, a, component)
+		pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 		if pairs != pairs2 || paths != paths2 || sum != sum2 {
 			t.Errorf("%2d: expected (%4d, %4d, %4d), computed (%4d, %4d, %4d)",
@@ -622,11 +670,11 @@ func testWheelGraphAll(t *testing.T, summer Summer) {
 }
 
 func TestWheelGraphAllV1(t *testing.T) {
-	testWheelGraphAll(t, sumAllSourcesAllShortestPathsV1)
+	testWheelGraphAll(t, allPaths(sumAllSourcesAllShortestPathsV1))
 }
 
 func TestWheelGraphAllV2(t *testing.T) {
-	testWheelGraphAll(t, sumAllSourcesAllShortestPathsV2)
+	testWheelGraphAll(t, allPaths(sumAllSourcesAllShortestPathsV2))
 }
 
 // 2DGrid graph (grid graph, square grid graph)
@@ -693,7 +741,7 @@ func test2DGraphOne(t *testing.T, summer Summer) {
 		sum := (2 * n * n * n * (n*n - 1)) / 3
 
 		node, a, component := build2DGridGraph(n, n)
-		pairs2, paths2, sum2 := summer(node, a, component)
+		pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 		if pairs != pairs2 || paths != paths2 || sum != sum2 {
 			t.Errorf("%2d: expected (%d, %d, %d), computed (%d, %d, %d)",
@@ -710,12 +758,16 @@ func Test2DGridGraphOneV2(t *testing.T) {
 	test2DGraphOne(t, sumAllSourcesShortestPathsV2)
 }
 
+func Test2DGridGraphOneV3(t *testing.T) {
+	test2DGraphOne(t, sumAllSourcesShortestPathsV3)
+}
+
 func test2DGraphAll(t *testing.T, summer Summer) {
 	for n := 1; n <= 10; n++ {
 		pairs, paths, sum := grid2DAllV2(n, n)
 
 		node, a, component := build2DGridGraph(n, n)
-		pairs2, paths2, sum2 := summer(node, a, component)
+		pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 		if pairs != pairs2 || paths != paths2 || sum != sum2 {
 			t.Errorf("%2d: expected (%12d, %12d, %12d), computed (%12d, %12d, %12d)",
@@ -724,6 +776,21 @@ func test2DGraphAll(t *testing.T, summer Summer) {
 	}
 }
 
+// binomial coefficient, number of ways to choose k of n
+func C(n, k int) int {
+	if k < 0 || k > n {
+		return 0
+	}
+	if k > n-k {
+		k = n - k
+	}
+	c := 1
+	for i := 1; i <= k; i++ {
+		c = c * (n - k + i) / i
+	}
+	return c
+}
+
 // determine pairs, paths, and sum of lengths analytically
 func grid2DAll(width, height int) (int, int, int) {
 	pairs := 0
@@ -777,11 +844,52 @@ func grid2DAllV2(width, height int) (int, int, int) {
 }
 
 func Test2DGridGraphAllV1(t *testing.T) {
-	test2DGraphAll(t, sumAllSourcesAllShortestPathsV1)
+	test2DGraphAll(t, allPaths(sumAllSourcesAllShortestPathsV1))
 }
 
 func Test2DGridGraphAllV2(t *testing.T) {
-	test2DGraphAll(t, sumAllSourcesAllShortestPathsV2)
+	test2DGraphAll(t, allPaths(sumAllSourcesAllShortestPathsV2))
+}
+
+// In a grid, the shortest paths between words dx and dy apart are the orderings of
+// dx steps across and dy down, dx+dy choose dx of them, which from corner to corner
+// of a 40 x 40 grid is more than 64 bits can count.
+func testGridGraphAllBig(t *testing.T, summer func([]string, adjacency, []Component) (int, *big.Int, *big.Int)) {
+	const nx, ny = 40, 40
+	paths, lengths := new(big.Int), new(big.Int)
+	for dx := 0; dx < nx; dx++ {
+		for dy := 0; dy < ny; dy++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			px, py := int64(nx), int64(ny) // ordered pairs of columns dx apart, rows dy apart
+			if dx > 0 {
+				px = 2 * int64(nx-dx)
+			}
+			if dy > 0 {
+				py = 2 * int64(ny-dy)
+			}
+			n := new(big.Int).Binomial(int64(dx+dy), int64(dx))
+			n.Mul(n, big.NewInt(px*py))
+			paths.Add(paths, n)
+			lengths.Add(lengths, n.Mul(n, big.NewInt(int64(dx+dy))))
+		}
+	}
+
+	node, a, component := build2DGridGraph(nx, ny)
+	pairs2, paths2, lengths2 := summer(searched(node, a, component))
+	if pairs2 != nx*ny*(nx*ny-1) || paths2.Cmp(paths) != 0 || lengths2.Cmp(lengths) != 0 {
+		t.Errorf("expected (%d, %d, %d), computed (%d, %d, %d)",
+			nx*ny*(nx*ny-1), paths, lengths, pairs2, paths2, lengths2)
+	}
+}
+
+func TestGridGraphAllBigV1(t *testing.T) {
+	testGridGraphAllBig(t, sumAllSourcesAllShortestPathsV1)
+}
+
+func TestGridGraphAllBigV2(t *testing.T) {
+	testGridGraphAllBig(t, sumAllSourcesAllShortestPathsV2)
 }
 
 // 3DGrid graph (grid graph, square grid graph)
@@ -847,7 +955,7 @@ func test3DGridGraphOne(t *testing.T, summer Summer) {
 		sum := n * n * n * n * n * (n*n - 1)
 
 		node, a, component := build3DGridGraph(n, n, n)
-		pairs2, paths2, sum2 := summer(node, a, component)
+		pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 		if pairs != pairs2 || paths != paths2 || sum != sum2 {
 			t.Errorf("%2d: expected (%12d, %12d, %12d), computed (%12d, %12d, %12d)",
@@ -864,12 +972,16 @@ func Test3DGridGraphOneV2(t *testing.T) {
 	test3DGridGraphOne(t, sumAllSourcesShortestPathsV2)
 }
 
+func Test3DGridGraphOneV3(t *testing.T) {
+	test3DGridGraphOne(t, sumAllSourcesShortestPathsV3)
+}
+
 func test3DGridGraphAll(t *testing.T, summer Summer) {
 	for n := 2; n <= 5; n++ {
 		pairs, paths, sum := grid3DAll(n, n, n)
 
 		node, a, component := build3DGridGraph(n, n, n)
-		pairs2, paths2, sum2 := summer(node, a, component)
+		pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 		if pairs != pairs2 || paths != paths2 || sum != sum2 {
 			t.Errorf("%2d: expected (%12d, %12d, %12d), computed (%12d, %12d, %12d)",
@@ -909,11 +1021,11 @@ func grid3DAll(width, height, depth int) (int, int, int) {
 }
 
 func Test3DGridGraphAllV1(t *testing.T) {
-	test3DGridGraphAll(t, sumAllSourcesAllShortestPathsV1)
+	test3DGridGraphAll(t, allPaths(sumAllSourcesAllShortestPathsV1))
 }
 
 func Test3DGridGraphAllV2(t *testing.T) {
-	test3DGridGraphAll(t, sumAllSourcesAllShortestPathsV2)
+	test3DGridGraphAll(t, allPaths(sumAllSourcesAllShortestPathsV2))
 }
 
 // Complete bipartite graph, K_{m,n}
@@ -976,7 +1088,7 @@ func testBipartiteGraphOne(t *testing.T, summer Summer) {
 			sum := 2 * (m*(m-1) + m*n + n*(n-1))
 
 			node, a, component := buildCompleteBipartiteGraph(m, n)
-			pairs2, paths2, sum2 := summer(node, a, component)
+			pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 			if pairs != pairs2 || paths != paths2 || sum != sum2 {
 				t.Errorf("{%2d,%2d}: expected (%d, %d, %d), computed (%d, %d, %d)",
@@ -994,6 +1106,10 @@ func TestBipartiteGraphOneV2(t *testing.T) {
 	testBipartiteGraphOne(t, sumAllSourcesShortestPathsV2)
 }
 
+func TestBipartiteGraphOneV3(t *testing.T) {
+	testBipartiteGraphOne(t, sumAllSourcesShortestPathsV3)
+}
+
 // Complete bipartite graph
 // Counting all shortest paths between each pair
 // Parameterized by n, the number of nodes
@@ -1019,7 +1135,7 @@ func testBipartiteGraphAll(t *testing.T, summer Summer) {
 			sum := 2 * m * n * (m + n - 1)
 
 			node, a, component := buildCompleteBipartiteGraph(m, n)
-			pairs2, paths2, sum2 := summer(node, a, component)
+			pairs2, paths2, sum2 := summer(searched(node, a, component))
 
 			if pairs != pairs2 || paths != paths2 || sum != sum2 {
 				t.Errorf("{%2d,%2d}: expected (%6d, %6d, %6d), computed (%6d, %6d, %6d)",
@@ -1030,11 +1146,116 @@ func testBipartiteGraphAll(t *testing.T, summer Summer) {
 }
 
 func TestBipartiteGraphAllV1(t *testing.T) {
-	testBipartiteGraphAll(t, sumAllSourcesAllShortestPathsV1)
+	testBipartiteGraphAll(t, allPaths(sumAllSourcesAllShortestPathsV1))
 }
 
 func TestBipartiteGraphAllV2(t *testing.T) {
-	testBipartiteGraphAll(t, sumAllSourcesAllShortestPathsV2)
+	testBipartiteGraphAll(t, allPaths(sumAllSourcesAllShortestPathsV2))
+}
+
+// Ladders between opposite corners of an n x n grid graph
+// Every shortest ladder is a monotone staircase of 2(n-1) steps,
+// n-1 of them in X, so there are C(2(n-1), n-1) of them.
+
+func TestGridLadders(t *testing.T) {
+	for n := 1; n <= 40; n++ {
+		_, a, component := build2DGridGraph(n, n)
+		adj := newAdjacency(a)
+		w1, w2 := Index(0), Index(n*n-1)
+		steps := 2 * (n - 1)
+		ways := new(big.Int).Binomial(int64(steps), int64(n-1))
+
+		ladder := findLadder(adj, component[0], w1, w2)
+		if len(ladder) != steps+1 || ladder[0] != w1 || ladder[steps] != w2 {
+			t.Errorf("%2d: expected %d-step ladder from %d to %d, found %v", n, steps, w1, w2, ladder)
+		}
+
+		count := countLadders(adj, component[0], w1, w2)
+		if count.Cmp(ways) != 0 {
+			t.Errorf("%2d: expected %v ladders, counted %v", n, ways, count)
+		}
+
+		if n <= 6 {
+			ladders := listLadders(adj, component[0], w1, w2, 0)
+			if int64(len(ladders)) != ways.Int64() {
+				t.Errorf("%2d: expected %v ladders, listed %d", n, ways, len(ladders))
+			}
+			for _, l := range ladders {
+				if len(l) != steps+1 || l[0] != w1 || l[steps] != w2 {
+					t.Errorf("%2d: expected %d-step ladder from %d to %d, listed %v", n, steps, w1, w2, l)
+				}
+			}
+		}
+
+		if limit := 5; ways.Cmp(big.NewInt(int64(limit))) > 0 {
+			if ladders := listLadders(adj, component[0], w1, w2, limit); len(ladders) != limit {
+				t.Errorf("%2d: expected %d ladders at limit, listed %d", n, limit, len(ladders))
+			}
+		}
+	}
+}
+
+type Histogrammer func(word []string, adj adjacency, component []Component) (int, int, []int)
+
+func TestDistanceHistogram(t *testing.T) {
+	for _, histogrammer := range []Histogrammer{histogramAllSourcesShortestPathsV1, histogramAllSourcesShortestPathsV2, histogramAllSourcesShortestPathsV3} {
+		for n := 3; n <= 40; n++ {
+			// path: 2(n-d) ordered pairs at each distance d
+			node, a, component := buildPathGraph(n)
+			_, _, histogram := histogrammer(searched(node, a, component))
+			for d := 1; d < n; d++ {
+				if len(histogram) != n || histogram[d] != 2*(n-d) {
+					t.Fatalf("path %d: expected %d pairs at distance %d, histogram %v", n, 2*(n-d), d, histogram)
+				}
+			}
+
+			// star: the n-1 spokes both ways at 1, pairs of distinct leaves at 2
+			node, a, component = buildStarGraph(n)
+			_, _, histogram = histogrammer(searched(node, a, component))
+			if len(histogram) != 3 || histogram[1] != 2*(n-1) || histogram[2] != (n-1)*(n-2) {
+				t.Fatalf("star %d: expected [0 %d %d], histogram %v", n, 2*(n-1), (n-1)*(n-2), histogram)
+			}
+
+			// complete: every pair at 1
+			node, a, component = buildCompleteGraph(n)
+			_, _, histogram = histogrammer(searched(node, a, component))
+			if len(histogram) != 2 || histogram[1] != n*(n-1) {
+				t.Fatalf("complete %d: expected [0 %d], histogram %v", n, n*(n-1), histogram)
+			}
+		}
+	}
+}
+
+// Searches from many sources at once span several batches in larger components.
+func TestMultiSourceBatches(t *testing.T) {
+	type graph struct {
+		name  string
+		build func() ([]string, adjacency, []Component)
+	}
+	word, err := readWords([]string{"words/webster-4"}, &Options{Length: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func(procs int) { MaxProcs = procs }(MaxProcs)
+	MaxProcs = 3 // more workers than processors here, as there may be
+	for _, g := range []graph{
+		{"path 1000", func() ([]string, adjacency, []Component) { return searched(buildPathGraph(1000)) }},
+		{"grid 37x41", func() ([]string, adjacency, []Component) { return searched(build2DGridGraph(37, 41)) }},
+		{"wheel 700", func() ([]string, adjacency, []Component) { return searched(buildWheelGraph(700)) }},
+		{"bipartite 200,300", func() ([]string, adjacency, []Component) { return searched(buildCompleteBipartiteGraph(200, 300)) }},
+		{"webster-4", func() ([]string, adjacency, []Component) {
+			adj, _ := findPairs(word, &Options{})
+			adj, component := relabel(adj, findComponents(word, adj, &Options{}))
+			return word, adj, component
+		}},
+	} {
+		node, adj, component := g.build()
+		pairs, sum, histogram := histogramAllSourcesShortestPathsV2(node, adj, component)
+		pairs3, sum3, histogram3 := histogramAllSourcesShortestPathsV3(node, adj, component)
+		if pairs != pairs3 || sum != sum3 || !reflect.DeepEqual(histogram, histogram3) {
+			t.Errorf("%s: expected (%d, %d, %v), computed (%d, %d, %v)", g.name, pairs, sum, histogram, pairs3, sum3, histogram3)
+		}
+	}
 }
 
 // fmt.Printf("//   %4d: %7d %10d %10d\n", n, pairs, paths, sum)
@@ -1049,14 +1270,15 @@ func TestBipartiteGraphAllV2(t *testing.T) {
 
 func benchmarkReadWords(b *testing.B, f string, length int) {
 	slice := []string{f}
-	word, _ := readWords(slice, length)
+	opt := &Options{Length: length}
+	word, _ := readWords(slice, opt)
 	if len(word) < 1 {
 		b.Logf("no words in input file")
 	}
 	b.ResetTimer()
 
 	for BN := 0; BN < b.N; BN++ {
-		readWords(slice, length)
+		readWords(slice, opt)
 	}
 }
 
@@ -1071,14 +1293,15 @@ func BenchmarkReadWords_webster8(b *testing.B) { benchmarkReadWords(b, "words/we
 func BenchmarkReadWords_webster9(b *testing.B) { benchmarkReadWords(b, "words/webster-9", 9) }
 
 func benchmarkFindPairs(b *testing.B, f string, length int) {
-	word, runes := readWords([]string{f}, length)
+	opt := &Options{Length: length}
+	word, _ := readWords([]string{f}, opt)
 	if len(word) < 1 {
 		b.Logf("no words in input file")
 	}
 	b.ResetTimer()
 
 	for BN := 0; BN < b.N; BN++ {
-		findPairs(word, runes)
+		findPairs(word, opt)
 	}
 }
 
@@ -1093,15 +1316,16 @@ func BenchmarkFindPairs_webster8(b *testing.B) { benchmarkFindPairs(b, "words/we
 func BenchmarkFindPairs_webster9(b *testing.B) { benchmarkFindPairs(b, "words/webster-9", 9) }
 
 func benchmarkFindComponents(b *testing.B, f string, length int) {
-	word, runes := readWords([]string{f}, length)
+	opt := &Options{Length: length}
+	word, _ := readWords([]string{f}, opt)
 	if len(word) < 1 {
 		b.Logf("no words in input file")
 	}
-	pair := findPairs(word, runes)
+	adj, _ := findPairs(word, opt)
 	b.ResetTimer()
 
 	for BN := 0; BN < b.N; BN++ {
-		findComponents(word, pair)
+		findComponents(word, adj, opt)
 	}
 }
 
@@ -1116,16 +1340,17 @@ func BenchmarkFindComponents_webster8(b *testing.B) { benchmarkFindComponents(b,
 func BenchmarkFindComponents_webster9(b *testing.B) { benchmarkFindComponents(b, "words/webster-9", 9) }
 
 func benchmarkSumASSPV1(b *testing.B, f string, length int) {
-	word, runes := readWords([]string{f}, length)
+	opt := &Options{Length: length}
+	word, _ := readWords([]string{f}, opt)
 	if len(word) < 1 {
 		b.Logf("no words in input file")
 	}
-	pair := findPairs(word, runes)
-	component := findComponents(word, pair)
+	adj, _ := findPairs(word, opt)
+	adj, component := relabel(adj, findComponents(word, adj, opt))
 	b.ResetTimer()
 
 	for BN := 0; BN < b.N; BN++ {
-		sumAllSourcesShortestPathsV1(word, pair, component)
+		sumAllSourcesShortestPathsV1(word, adj, component)
 	}
 }
 
@@ -1140,16 +1365,17 @@ func BenchmarkSumASSPV1_webster8(b *testing.B) { benchmarkSumASSPV1(b, "words/we
 func BenchmarkSumASSPV1_webster9(b *testing.B) { benchmarkSumASSPV1(b, "words/webster-9", 9) }
 
 func benchmarkSumASSPV2(b *testing.B, f string, length int) {
-	word, runes := readWords([]string{f}, length)
+	opt := &Options{Length: length}
+	word, _ := readWords([]string{f}, opt)
 	if len(word) < 1 {
 		b.Logf("no words in input file")
 	}
-	pair := findPairs(word, runes)
-	component := findComponents(word, pair)
+	adj, _ := findPairs(word, opt)
+	adj, component := relabel(adj, findComponents(word, adj, opt))
 	b.ResetTimer()
 
 	for BN := 0; BN < b.N; BN++ {
-		sumAllSourcesShortestPathsV2(word, pair, component)
+		sumAllSourcesShortestPathsV2(word, adj, component)
 	}
 }
 
@@ -1163,16 +1389,41 @@ func BenchmarkSumASSPV2_webster7(b *testing.B) { benchmarkSumASSPV2(b, "words/we
 func BenchmarkSumASSPV2_webster8(b *testing.B) { benchmarkSumASSPV2(b, "words/webster-8", 8) }
 func BenchmarkSumASSPV2_webster9(b *testing.B) { benchmarkSumASSPV2(b, "words/webster-9", 9) }
 
+func benchmarkSumASSPV3(b *testing.B, f string, length int) {
+	opt := &Options{Length: length}
+	word, _ := readWords([]string{f}, opt)
+	if len(word) < 1 {
+		b.Logf("no words in input file")
+	}
+	adj, _ := findPairs(word, opt)
+	adj, component := relabel(adj, findComponents(word, adj, opt))
+	b.ResetTimer()
+
+	for BN := 0; BN < b.N; BN++ {
+		sumAllSourcesShortestPathsV3(word, adj, component)
+	}
+}
+
+func BenchmarkSumASSPV3_webster1(b *testing.B) { benchmarkSumASSPV3(b, "words/webster-1", 1) }
+func BenchmarkSumASSPV3_webster2(b *testing.B) { benchmarkSumASSPV3(b, "words/webster-2", 2) }
+func BenchmarkSumASSPV3_webster3(b *testing.B) { benchmarkSumASSPV3(b, "words/webster-3", 3) }
+func BenchmarkSumASSPV3_webster4(b *testing.B) { benchmarkSumASSPV3(b, "words/webster-4", 4) }
+func BenchmarkSumASSPV3_webster5(b *testing.B) { benchmarkSumASSPV3(b, "words/webster-5", 5) }
+func BenchmarkSumASSPV3_webster6(b *testing.B) { benchmarkSumASSPV3(b, "words/webster-6", 6) }
+func BenchmarkSumASSPV3_webster7(b *testing.B) { benchmarkSumASSPV3(b, "words/webster-7", 7) }
+func BenchmarkSumASSPV3_webster8(b *testing.B) { benchmarkSumASSPV3(b, "words/webster-8", 8) }
+func BenchmarkSumASSPV3_webster9(b *testing.B) { benchmarkSumASSPV3(b, "words/webster-9", 9) }
+
 //
 // Benchmark the solving of various simple graph types
 //
 
 func benchmarkSumPathV1(b *testing.B, n int) {
-	node, a, component := buildPathGraph(n)
+	node, adj, component := searched(buildPathGraph(n))
 	b.ResetTimer()
 
 	for BN := 0; BN < b.N; BN++ {
-		sumAllSourcesShortestPathsV1(node, a, component)
+		sumAllSourcesShortestPathsV1(node, adj, component)
 	}
 }
 
@@ -1187,11 +1438,11 @@ func BenchmarkSumPathV1_16000(b *testing.B) { benchmarkSumPathV1(b, 16000) }
 func BenchmarkSumPathV1_18000(b *testing.B) { benchmarkSumPathV1(b, 18000) }
 
 func benchmarkSumPathV2(b *testing.B, n int) {
-	node, a, component := buildPathGraph(n)
+	node, adj, component := searched(buildPathGraph(n))
 	b.ResetTimer()
 
 	for BN := 0; BN < b.N; BN++ {
-		sumAllSourcesShortestPathsV2(node, a, component)
+		sumAllSourcesShortestPathsV2(node, adj, component)
 	}
 }
 
@@ -1205,6 +1456,25 @@ func BenchmarkSumPathV2_14000(b *testing.B) { benchmarkSumPathV2(b, 14000) }
 func BenchmarkSumPathV2_16000(b *testing.B) { benchmarkSumPathV2(b, 16000) }
 func BenchmarkSumPathV2_18000(b *testing.B) { benchmarkSumPathV2(b, 18000) }
 
+func benchmarkSumPathV3(b *testing.B, n int) {
+	node, adj, component := searched(buildPathGraph(n))
+	b.ResetTimer()
+
+	for BN := 0; BN < b.N; BN++ {
+		sumAllSourcesShortestPathsV3(node, adj, component)
+	}
+}
+
+func BenchmarkSumPathV3_2000(b *testing.B)  { benchmarkSumPathV3(b, 2000) }
+func BenchmarkSumPathV3_4000(b *testing.B)  { benchmarkSumPathV3(b, 4000) }
+func BenchmarkSumPathV3_6000(b *testing.B)  { benchmarkSumPathV3(b, 6000) }
+func BenchmarkSumPathV3_8000(b *testing.B)  { benchmarkSumPathV3(b, 8000) }
+func BenchmarkSumPathV3_10000(b *testing.B) { benchmarkSumPathV3(b, 10000) }
+func BenchmarkSumPathV3_12000(b *testing.B) { benchmarkSumPathV3(b, 12000) }
+func BenchmarkSumPathV3_14000(b *testing.B) { benchmarkSumPathV3(b, 14000) }
+func BenchmarkSumPathV3_16000(b *testing.B) { benchmarkSumPathV3(b, 16000) }
+func BenchmarkSumPathV3_18000(b *testing.B) { benchmarkSumPathV3(b, 18000) }
+
 // func benchmarkSumCycleV1(b *testing.B, n int) {
 // 	node, a, component := buildCycleGraph(n)
 // 	b.ResetTimer()
@@ -1362,11 +1632,11 @@ func BenchmarkSumPathV2_18000(b *testing.B) { benchmarkSumPathV2(b, 18000) }
 // func BenchmarkSumWheelV2_18000(b *testing.B) { benchmarkSumWheelV2(b, 18000) }
 
 func benchmarkSum2DGridV1(b *testing.B, n int) {
-	node, a, component := build2DGridGraph(n, n)
+	node, adj, component := searched(build2DGridGraph(n, n))
 	b.ResetTimer()
 
 	for BN := 0; BN < b.N; BN++ {
-		sumAllSourcesShortestPathsV1(node, a, component)
+		sumAllSourcesShortestPathsV1(node, adj, component)
 	}
 }
 
@@ -1384,11 +1654,11 @@ func BenchmarkSum2DGridV1_90(b *testing.B)  { benchmarkSum2DGridV1(b, 90) }
 func BenchmarkSum2DGridV1_100(b *testing.B) { benchmarkSum2DGridV1(b, 100) }
 
 func benchmarkSum2DGridV2(b *testing.B, n int) {
-	node, a, component := build2DGridGraph(n, n)
+	node, adj, component := searched(build2DGridGraph(n, n))
 	b.ResetTimer()
 
 	for BN := 0; BN < b.N; BN++ {
-		sumAllSourcesShortestPathsV2(node, a, component)
+		sumAllSourcesShortestPathsV2(node, adj, component)
 	}
 }
 
@@ -1405,12 +1675,34 @@ func BenchmarkSum2DGridV2_80(b *testing.B)  { benchmarkSum2DGridV2(b, 80) }
 func BenchmarkSum2DGridV2_90(b *testing.B)  { benchmarkSum2DGridV2(b, 90) }
 func BenchmarkSum2DGridV2_100(b *testing.B) { benchmarkSum2DGridV2(b, 100) }
 
+func benchmarkSum2DGridV3(b *testing.B, n int) {
+	node, adj, component := searched(build2DGridGraph(n, n))
+	b.ResetTimer()
+
+	for BN := 0; BN < b.N; BN++ {
+		sumAllSourcesShortestPathsV3(node, adj, component)
+	}
+}
+
+func BenchmarkSum2DGridV3_4(b *testing.B)   { benchmarkSum2DGridV3(b, 4) }
+func BenchmarkSum2DGridV3_6(b *testing.B)   { benchmarkSum2DGridV3(b, 6) }
+func BenchmarkSum2DGridV3_8(b *testing.B)   { benchmarkSum2DGridV3(b, 8) }
+func BenchmarkSum2DGridV3_10(b *testing.B)  { benchmarkSum2DGridV3(b, 10) }
+func BenchmarkSum2DGridV3_12(b *testing.B)  { benchmarkSum2DGridV3(b, 12) }
+func BenchmarkSum2DGridV3_20(b *testing.B)  { benchmarkSum2DGridV3(b, 20) }
+func BenchmarkSum2DGridV3_40(b *testing.B)  { benchmarkSum2DGridV3(b, 40) }
+func BenchmarkSum2DGridV3_60(b *testing.B)  { benchmarkSum2DGridV3(b, 60) }
+func BenchmarkSum2DGridV3_70(b *testing.B)  { benchmarkSum2DGridV3(b, 70) }
+func BenchmarkSum2DGridV3_80(b *testing.B)  { benchmarkSum2DGridV3(b, 80) }
+func BenchmarkSum2DGridV3_90(b *testing.B)  { benchmarkSum2DGridV3(b, 90) }
+func BenchmarkSum2DGridV3_100(b *testing.B) { benchmarkSum2DGridV3(b, 100) }
+
 func benchmarkSumAll2DGridV1(b *testing.B, n int) {
-	node, a, component := build2DGridGraph(n, n)
+	node, adj, component := searched(build2DGridGraph(n, n))
 	b.ResetTimer()
 
 	for BN := 0; BN < b.N; BN++ {
-		sumAllSourcesAllShortestPathsV1(node, a, component)
+		sumAllSourcesAllShortestPathsV1(node, adj, component)
 	}
 }
 
@@ -1421,11 +1713,11 @@ func BenchmarkSumAll2DGridV1_10(b *testing.B) { benchmarkSumAll2DGridV1(b, 10) }
 func BenchmarkSumAll2DGridV1_12(b *testing.B) { benchmarkSumAll2DGridV1(b, 12) }
 
 func benchmarkSumAll2DGridV2(b *testing.B, n int) {
-	node, a, component := build2DGridGraph(n, n)
+	node, adj, component := searched(build2DGridGraph(n, n))
 	b.ResetTimer()
 
 	for BN := 0; BN < b.N; BN++ {
-		sumAllSourcesAllShortestPathsV1(node, a, component)
+		sumAllSourcesAllShortestPathsV1(node, adj, component)
 	}
 }
 
@@ -0,0 +1,206 @@
+package ladder
+
+/*
+ * matrix.go -- all pairs shortest path distance matrices, written to disk
+ */
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// MAXDIST is the largest distance a distance matrix can hold. Ladder graphs have
+// small diameters so one byte per distance suffices; longer distances saturate.
+const MAXDIST = 255
+
+// magic number that begins each component's distance matrix
+const distancesMagic = "LADDERD1"
+
+// the most words ReadDistances accepts in a matrix, far more than any component of
+// a word graph, and few enough that the number of distances fits in an int64
+const maxDistanceWords = 1 << 20
+
+// Distances is the all pairs shortest path distance matrix of one component.
+type Distances struct {
+	Word     []string // words of the rows (and columns), in order
+	Distance []uint8  // row-major distances, len(Word) * len(Word) of them
+}
+
+// At returns the distance from the word of row i to the word of column j.
+func (d *Distances) At(i, j int) int {
+	return int(d.Distance[i*len(d.Word)+j])
+}
+
+// WriteDistances writes the distance matrix of component cn (a position in the list
+// returned by Components) to w in a compact binary format:
+//
+//	"LADDERD1"          magic number (8 bytes)
+//	n                   number of words (uint32, little-endian)
+//	n words             each as its length in bytes (uvarint) and UTF-8 text
+//	n*n distances       row-major, one byte each, saturating at MAXDIST
+//
+// Matrices of several components may be written one after another to one file.
+// Rows are found by parallel BFS from each word, a block of rows at a time, so
+// that memory is proportional to the number of words rather than its square.
+func (g *Graph) WriteDistances(w io.Writer, cn int) error {
+	c := g.Components()[cn]
+	b := bufio.NewWriter(w)
+
+	// header: magic number, word count, and words of the rows
+	b.WriteString(distancesMagic)
+	var buf [binary.MaxVarintLen64]byte
+	binary.LittleEndian.PutUint32(buf[:4], uint32(c.words))
+	b.Write(buf[:4])
+	for _, wn := range c.word {
+		n := binary.PutUvarint(buf[:], uint64(len(g.word[wn])))
+		b.Write(buf[:n])
+		b.WriteString(g.word[wn])
+	}
+
+	// body: rows of the matrix, computed a block at a time
+	block := minInt(c.words, 64*MaxProcs)
+	row := make([][]uint8, block)
+	for i := range row {
+		row[i] = make([]uint8, c.words)
+	}
+	for first := 0; first < c.words; first += block {
+		rows := minInt(block, c.words-first)
+		ssspRowsParallel(g.word, g.adj, c, c.word[first:first+rows], row[:rows])
+		for _, r := range row[:rows] {
+			b.Write(r)
+		}
+	}
+	return b.Flush()
+}
+
+// Compute the distance matrix rows of source words in component c, in parallel.
+// The columns of each row follow the order of the component's words.
+func ssspRowsParallel(word []string, adj adjacency, c Component, source Indexes, row [][]uint8) {
+	tasks := make(chan int)
+	results := make(chan bool)
+
+	// start workers
+	workers := minInt(len(source), MaxProcs)
+	for i := 0; i < workers; i++ {
+		go func(id int, in chan int, out chan bool) {
+			distance := make(Indexes, len(word))
+			queue := make(Indexes, len(c.word))
+			done := make([]bool, len(word))
+			for r := range in {
+				ssspBFS(c.word, adj, source[r], distance, queue, done, nil)
+				for j, wn := range c.word {
+					row[r][j] = uint8(minIndex(distance[wn], MAXDIST))
+				}
+				out <- true
+			}
+		}(i, tasks, results)
+	}
+
+	// start dispatcher
+	go func(out chan int) {
+		for r := range source {
+			out <- r
+		}
+		close(out)
+	}(tasks)
+
+	// harvest results from workers
+	for _ = range source {
+		<-results
+	}
+	close(results)
+}
+
+// ReadDistances reads one component's distance matrix, as written by WriteDistances,
+// from r. It returns io.EOF when there are no more matrices. To read several matrices
+// from one file, pass the same io.ByteReader, such as a *bufio.Reader, each time;
+// any other reader is buffered afresh and may lose data read ahead.
+func ReadDistances(r io.Reader) (*Distances, error) {
+	left, known := remaining(r)
+	b, ok := r.(io.ByteReader)
+	if !ok {
+		br := bufio.NewReader(r)
+		r, b = br, br
+	}
+
+	var magic [len(distancesMagic)]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, err // io.EOF when no more matrices
+	}
+	if string(magic[:]) != distancesMagic {
+		return nil, errors.New("not a distance matrix")
+	}
+	var count uint32
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return nil, unexpected(err)
+	}
+
+	// the count is checked before anything is allocated by it: each word takes at
+	// least a byte, and each distance one
+	words := int64(count)
+	if words > maxDistanceWords || uint64(words*words) > math.MaxInt {
+		return nil, fmt.Errorf("distance matrix has too many words (%d)", count)
+	}
+	if left -= int64(len(magic) + 4); known && words+words*words > left {
+		return nil, fmt.Errorf("distance matrix of %d words is longer than the %d bytes left", count, left)
+	}
+
+	// when the length is not known, memory grows only as the data arrives, so a
+	// corrupt count runs out of data rather than memory
+	d := &Distances{Word: make([]string, 0, minInt(int(count), 1<<16))}
+	for i := 0; i < int(count); i++ {
+		n, err := binary.ReadUvarint(b)
+		if err != nil {
+			return nil, unexpected(err)
+		}
+		if n > 1<<16 {
+			return nil, fmt.Errorf("word %d of distance matrix is too long (%d bytes)", i, n)
+		}
+		s := make([]byte, n)
+		if _, err := io.ReadFull(r, s); err != nil {
+			return nil, unexpected(err)
+		}
+		d.Word = append(d.Word, string(s))
+	}
+	distances := int(count) * int(count)
+	d.Distance = make([]uint8, 0, minInt(distances, 1<<20))
+	for len(d.Distance) < distances {
+		n := len(d.Distance)
+		d.Distance = append(d.Distance, make([]uint8, minInt(distances-n, maxInt(n, 1<<20)))...)
+		if _, err := io.ReadFull(r, d.Distance[n:]); err != nil {
+			return nil, unexpected(err)
+		}
+	}
+	return d, nil
+}
+
+// the number of bytes left to read from r, when it can tell
+func remaining(r io.Reader) (int64, bool) {
+	switch r := r.(type) {
+	case interface{ Len() int }: // bytes.Reader, bytes.Buffer, strings.Reader
+		return int64(r.Len()), true
+	case io.Seeker: // os.File, when it is a regular file
+		at, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		end, err := r.Seek(0, io.SeekEnd)
+		if _, err2 := r.Seek(at, io.SeekStart); err != nil || err2 != nil {
+			return 0, false
+		}
+		return end - at, true
+	}
+	return 0, false
+}
+
+// a matrix that ends early is corrupt rather than the end of the file
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+package ladder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// a graph of named, linked nodes with its components already known
+func namedGraph(build Builder) *Graph {
+	node, a, component := build()
+	for i := range node {
+		node[i] = fmt.Sprintf("n%d", i)
+	}
+	adj := newAdjacency(a)
+	tag := make([]Edge, len(adj.link))
+	for i := range tag {
+		tag[i] = newEdge(Link, 0)
+	}
+	g := &Graph{word: node, adj: adj, tag: tag}
+	g.once.Do(func() { g.component = component })
+	return g
+}
+
+func TestDistances(t *testing.T) {
+	const nx, ny, n = 12, 10, 50 // enough grid rows to need several blocks
+	grid := namedGraph(func() ([]string, []Indexes, []Component) { return build2DGridGraph(nx, ny) })
+	path := namedGraph(func() ([]string, []Indexes, []Component) { return buildPathGraph(n) })
+
+	// write two matrices to one file and read them back
+	var b bytes.Buffer
+	if err := grid.WriteDistances(&b, 0); err != nil {
+		t.Fatal(err)
+	}
+	gridSize := b.Len()
+	if err := path.WriteDistances(&b, 0); err != nil {
+		t.Fatal(err)
+	}
+	data := b.Bytes()
+
+	r := bytes.NewReader(data)
+	d, err := ReadDistances(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Word) != nx*ny || d.Word[nx+1] != "n13" {
+		t.Fatalf("grid: read %d words, row %d is %q", len(d.Word), nx+1, d.Word[nx+1])
+	}
+	for i := 0; i < nx*ny; i++ {
+		for j := 0; j < nx*ny; j++ {
+			expected := absInt(i%nx-j%nx) + absInt(i/nx-j/nx)
+			if d.At(i, j) != expected {
+				t.Fatalf("grid: distance(%d, %d) expected %d, read %d", i, j, expected, d.At(i, j))
+			}
+		}
+	}
+
+	d, err = ReadDistances(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if d.At(i, j) != absInt(i-j) {
+				t.Fatalf("path: distance(%d, %d) expected %d, read %d", i, j, absInt(i-j), d.At(i, j))
+			}
+		}
+	}
+
+	if _, err = ReadDistances(r); err != io.EOF {
+		t.Errorf("after last matrix: expected io.EOF, got %v", err)
+	}
+	if _, err = ReadDistances(bytes.NewReader(data[:gridSize-1])); err != io.ErrUnexpectedEOF {
+		t.Errorf("truncated matrix: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+
+	// a corrupt word count is rejected before it is allocated, by a limit, by the
+	// length of the data when known, and otherwise by running out of data
+	header := func(count uint32) []byte {
+		h := append([]byte(distancesMagic), 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(h[len(distancesMagic):], count)
+		return append(h, data[len(h):gridSize]...)
+	}
+	for _, test := range []struct {
+		name  string
+		count uint32
+		r     func([]byte) io.Reader
+		err   error
+	}{
+		{"too many words", 1<<32 - 1, func(b []byte) io.Reader { return bytes.NewReader(b) }, nil},
+		{"longer than data", 1<<20 - 1, func(b []byte) io.Reader { return bytes.NewReader(b) }, nil},
+		{"longer than stream", 1<<20 - 1, func(b []byte) io.Reader { return io.MultiReader(bytes.NewReader(b)) }, io.ErrUnexpectedEOF},
+	} {
+		if _, err := ReadDistances(test.r(header(test.count))); err == nil || (test.err != nil && err != test.err) {
+			t.Errorf("%s: expected error %v, got %v", test.name, test.err, err)
+		}
+	}
+}
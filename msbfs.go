@@ -0,0 +1,228 @@
+package ladder
+
+/*
+ * msbfs.go -- all sources shortest paths by bit-parallel multi-source BFS
+ */
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// The number of 64-bit words of sources searched at once, for 256 sources in all.
+// Wider batches traverse a component fewer times, but each step carries more bits.
+const msLanes = 4
+
+// Scratch space for a multi-source breadth first search (MS-BFS), which runs the
+// searches from a batch of sources together, one bit for each. For each word of
+// a component it keeps the sources that have reached it, those that reached it at
+// the current distance, and those that reach it at the next. Words are found by
+// their position in the component, whose words must be numbered consecutively, as
+// relabel numbers them.
+type msSearch struct {
+	seen     []uint64
+	visit    []uint64
+	next     []uint64
+	frontier Indexes // words reached by some source at the current distance
+	reached  Indexes // words reached by some source at the next
+}
+
+func newMSSearch(words int) *msSearch {
+	return &msSearch{
+		seen:     make([]uint64, words*msLanes),
+		visit:    make([]uint64, words*msLanes),
+		next:     make([]uint64, words*msLanes),
+		frontier: make(Indexes, 0, words),
+		reached:  make(Indexes, 0, words),
+	}
+}
+
+// Search from the words of component c from position first on, up to 64*msLanes of
+// them, and return the sum of the lengths of the shortest paths from each to every
+// other word. When histogram is not nil, add the number of pairs found at each
+// distance d to histogram[d]. Each step of the search carries the bits of every
+// source reaching a word to its neighbors, so the component is traversed once per
+// distance for the whole batch rather than once for each source.
+func (m *msSearch) search(adj adjacency, c Component, first int, histogram []int) int {
+	base := c.word[0]
+	sources := minInt(c.words-first, 64*msLanes)
+	lanes := (sources + 63) / 64
+	seen, visit, next := m.seen[:c.words*lanes], m.visit[:c.words*lanes], m.next[:c.words*lanes]
+	for i := range seen {
+		seen[i] = 0
+		visit[i] = 0 // next is left clear by each step
+	}
+
+	// each source has reached itself
+	frontier := m.frontier[:0]
+	for s := 0; s < sources; s++ {
+		v := first + s
+		bit := uint64(1) << uint(s%64)
+		seen[v*lanes+s/64] = bit
+		visit[v*lanes+s/64] = bit
+		frontier = append(frontier, Index(v))
+	}
+
+	total := 0
+	for d := 1; len(frontier) > 0; d++ {
+		// carry the sources reaching each word of the frontier to its neighbors
+		reached := m.reached[:0]
+		for _, v := range frontier {
+			from := visit[int(v)*lanes : int(v+1)*lanes]
+			for _, wn := range adj.link[adj.first[base+v]:adj.first[base+v+1]] {
+				u := int(wn-base) * lanes
+				to := next[u : u+lanes]
+				if isZero(to) {
+					reached = append(reached, wn-base)
+				}
+				for k, b := range from {
+					to[k] |= b
+				}
+			}
+			for k := range from {
+				from[k] = 0
+			}
+		}
+
+		// keep the sources that reach a word for the first time
+		found := 0
+		frontier = frontier[:0]
+		for _, v := range reached {
+			u := int(v) * lanes
+			var reach uint64
+			for k := u; k < u+lanes; k++ {
+				newly := next[k] &^ seen[k]
+				seen[k] |= newly
+				visit[k] = newly
+				next[k] = 0
+				reach |= newly
+				found += bits.OnesCount64(newly)
+			}
+			if reach != 0 {
+				frontier = append(frontier, v)
+			}
+		}
+		m.reached = reached
+		total += d * found
+		if histogram != nil && found > 0 {
+			histogram[d] += found
+		}
+	}
+	m.frontier = frontier
+	return total
+}
+
+func isZero(a []uint64) bool {
+	for _, b := range a {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// The fewest words of a component searched from many sources at once. Batches from
+// smaller components would fill less than one word of source bits, and these are
+// searched from one source at a time instead.
+var msBreakpoint = 64
+
+// Sum the length of one shortest path between each pair of words, searching the
+// components of at least msBreakpoint words from many words at once and the others
+// from one at a time. The words of each component must be numbered together, as
+// relabel numbers them.
+func sumAllSourcesShortestPaths(word []string, adj adjacency, component []Component) (int, int, int) {
+	totalPairs, totalPaths, _ := histogramAllSourcesShortestPaths(word, adj, component)
+	return totalPairs, totalPairs, totalPaths
+}
+
+// Count the pairs at each distance as well, with histogramAllSourcesShortestPathsV3
+// for the larger components and histogramAllSourcesShortestPathsV2 for the rest.
+func histogramAllSourcesShortestPaths(word []string, adj adjacency, component []Component) (int, int, []int) {
+	// components are sorted largest first
+	i := sort.Search(len(component), func(i int) bool { return component[i].words < msBreakpoint })
+	totalPairs, totalPaths, histogram := histogramAllSourcesShortestPathsV3(word, adj, component[:i])
+	pairs, paths, small := histogramAllSourcesShortestPathsV2(word, adj, component[i:])
+	if len(small) > len(histogram) {
+		histogram, small = small, histogram
+	}
+	addHistogram(histogram, small)
+	return totalPairs + pairs, totalPaths + paths, histogram
+}
+
+// Sum the length of one shortest path between each pair of words, as
+// sumAllSourcesShortestPathsV2 does, but searching from many words at once. The
+// words of each component must be numbered together, as relabel numbers them.
+func sumAllSourcesShortestPathsV3(word []string, adj adjacency, component []Component) (int, int, int) {
+	totalPairs, totalPaths, _ := histogramAllSourcesShortestPathsV3(word, adj, component)
+	return totalPairs, totalPairs, totalPaths
+}
+
+// a batch of sources for a multi-source search: the words of c from position first
+type sourceBatch struct {
+	c     Component
+	first int
+}
+
+// Multi-source version of histogramAllSourcesShortestPathsV2, with the searches of
+// each batch of sources in a worker. Each worker counts distances in its own
+// histogram, merged into histogram at the end. The words of each component must be
+// numbered together, as for sumAllSourcesShortestPathsV3.
+func histogramAllSourcesShortestPathsV3(word []string, adj adjacency, component []Component) (int, int, []int) {
+	var totalPairs, totalPaths int
+	if len(component) == 0 {
+		return 0, 0, nil
+	}
+	histogram := make([]int, component[0].words) // distances are less than the words
+
+	batches := 0
+	for _, c := range component {
+		if c.words > 2 {
+			batches += (c.words + 64*msLanes - 1) / (64 * msLanes)
+		}
+	}
+	tasks := make(chan sourceBatch)
+	results := make(chan int)
+	histograms := make(chan []int)
+
+	// start workers
+	workers := minInt(batches, MaxProcs)
+	for i := 0; i < workers; i++ {
+		go func(id int, in chan sourceBatch, out chan int, adj adjacency) {
+			m := newMSSearch(component[0].words) // components are sorted largest first
+			count := make([]int, component[0].words)
+			for b := range in {
+				out <- m.search(adj, b.c, b.first, count)
+			}
+			histograms <- count
+		}(i, tasks, results, adj)
+	}
+
+	// start dispatcher
+	go func(out chan sourceBatch) {
+		for _, c := range component {
+			for first := 0; c.words > 2 && first < c.words; first += 64 * msLanes {
+				out <- sourceBatch{c, first}
+			}
+		}
+		close(out)
+	}(tasks)
+
+	// harvest results from workers
+	for i := 0; i < batches; i++ {
+		totalPaths += <-results
+	}
+	close(results)
+	for i := 0; i < workers; i++ {
+		addHistogram(histogram, <-histograms)
+	}
+
+	// count the pairs, and solve small (nodes <= 2) problems directly
+	for _, c := range component {
+		totalPairs += c.words * (c.words - 1)
+		if c.words == 2 { // single pair of words with two length 1 solutions (a->b and b->a)
+			totalPaths += 2
+			histogram[1] += 2
+		}
+	}
+	return totalPairs, totalPaths, trimHistogram(histogram)
+}
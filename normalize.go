@@ -0,0 +1,187 @@
+package ladder
+
+/*
+ * normalize.go -- split text into words and put them in a standard form
+ */
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultCutset is the runes that separate words when a Normalizer has no Cutset:
+// white space, digits, and punctuation other than apostrophes.
+const DefaultCutset = " \t\n\r0123456789`~!@#$%^&*()-—_=+[{]}\\|;:\",<.>/?"
+
+// A Normalizer describes how text is split into words and how each word is made
+// standard. Its zero value splits words at DefaultCutset and at hyphens, removes
+// apostrophes ("o'clock" is "oclock"), and lowers the case of letters.
+type Normalizer struct {
+	Form            Form    // Unicode normalization of each word
+	Fold            bool    // remove diacritics, so "café" is "cafe"
+	KeepCase        bool    // keep upper case letters rather than lowering them
+	KeepApostrophes bool    // keep apostrophes within words rather than removing them
+	Letters         bool    // separate words at every rune but letters and marks, rather than at Cutset
+	Cutset          string  // runes that separate words (DefaultCutset when empty)
+	Hyphens         Hyphens // treatment of hyphens, whatever the cutset
+}
+
+// Hyphens selects how hyphenated words such as "well-known" are read.
+type Hyphens int
+
+const (
+	SplitHyphens Hyphens = iota // as two words, "well" and "known"
+	JoinHyphens                 // as one word without the hyphen, "wellknown"
+	KeepHyphens                 // as one word with the hyphen, "well-known"
+)
+
+var hyphensName = []string{"split", "join", "keep"}
+
+func (h Hyphens) String() string {
+	if h < 0 || int(h) >= len(hyphensName) {
+		return "Hyphens(" + strconv.Itoa(int(h)) + ")"
+	}
+	return hyphensName[h]
+}
+
+// ParseHyphens returns the Hyphens named "split", "join", or "keep".
+func ParseHyphens(name string) (Hyphens, error) {
+	for h, s := range hyphensName {
+		if strings.EqualFold(name, s) {
+			return Hyphens(h), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown hyphen rule %q (want split, join, or keep)", name)
+}
+
+func isHyphen(r rune) bool     { return r == '-' || r == '‐' } // hyphen-minus and hyphen
+func isApostrophe(r rune) bool { return r == '\'' || r == '’' }
+
+// separates reports whether r separates words.
+func (n *Normalizer) separates(r rune) bool {
+	switch {
+	case isHyphen(r):
+		return n.Hyphens == SplitHyphens
+	case n.Letters:
+		return !isApostrophe(r) && !unicode.IsLetter(r) && !unicode.IsMark(r)
+	case n.Cutset == "":
+		return strings.ContainsRune(DefaultCutset, r)
+	}
+	return strings.ContainsRune(n.Cutset, r)
+}
+
+// Split is a bufio.SplitFunc that returns each word of the text, as separated by
+// the Normalizer's Letters, Cutset, and Hyphens, without normalizing it.
+func (n *Normalizer) Split(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	return scanSeparated(data, atEOF, n.separates)
+}
+
+// Word returns the standard form of a word split from text.
+func (n *Normalizer) Word(s string) string {
+	if !n.KeepApostrophes {
+		s = strings.Map(func(r rune) rune {
+			if isApostrophe(r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	if n.Hyphens == JoinHyphens {
+		s = strings.Map(func(r rune) rune {
+			if isHyphen(r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	if n.Fold {
+		s = n.Form.fold(s)
+	} else {
+		s = n.Form.Normalize(s)
+	}
+	if !n.KeepCase {
+		s = strings.ToLower(s)
+	}
+	return s
+}
+
+// Form is a Unicode normalization form, as implemented by golang.org/x/text/unicode/norm.
+type Form int
+
+const (
+	NoForm Form = iota // text as read
+	NFC                // canonical decomposition, then canonical composition
+	NFD                // canonical decomposition
+	NFKC               // compatibility decomposition, then canonical composition
+	NFKD               // compatibility decomposition
+)
+
+var formName = []string{"none", "NFC", "NFD", "NFKC", "NFKD"}
+
+func (f Form) String() string {
+	if f < 0 || int(f) >= len(formName) {
+		return "Form(" + strconv.Itoa(int(f)) + ")"
+	}
+	return formName[f]
+}
+
+// ParseForm returns the Form named "none", "NFC", "NFD", "NFKC", or "NFKD", in
+// either case.
+func ParseForm(name string) (Form, error) {
+	for f, s := range formName {
+		if strings.EqualFold(name, s) {
+			return Form(f), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown normalization form %q (want none, NFC, NFD, NFKC, or NFKD)", name)
+}
+
+// the x/text form of each Form but NoForm
+var textForm = [...]norm.Form{NFC: norm.NFC, NFD: norm.NFD, NFKC: norm.NFKC, NFKD: norm.NFKD}
+
+// Normalize returns s in normalization form f. An unknown form, like NoForm, leaves
+// s unchanged.
+func (f Form) Normalize(s string) string {
+	if f <= NoForm || int(f) >= len(textForm) || isASCII(s) {
+		return s
+	}
+	return textForm[f].String(s)
+}
+
+// fold returns s in normalization form f without the nonspacing marks that are
+// its diacritics. It is composed unless f is a decomposed form. Letters that do
+// not decompose, such as 'ø' and 'ł', keep their diacritics.
+func (f Form) fold(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	decompose, compose := norm.NFD, norm.NFC
+	if f == NFKC || f == NFKD {
+		decompose, compose = norm.NFKD, norm.NFKC
+	}
+	t := transform.Chain(decompose, runes.Remove(runes.In(unicode.Mn)))
+	if f != NFD && f != NFKD {
+		t = transform.Chain(t, compose)
+	}
+	bare, _, err := transform.String(t, s)
+	if err != nil { // only on invalid UTF-8, which is kept as read
+		return s
+	}
+	return bare
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
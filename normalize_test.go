@@ -0,0 +1,83 @@
+package ladder
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestForms(t *testing.T) {
+	tests := []struct {
+		s                    string
+		nfc, nfd, nfkc, nfkd string
+	}{
+		{"caf\u00e9", "caf\u00e9", "cafe\u0301", "caf\u00e9", "cafe\u0301"},
+		{"cafe\u0301", "caf\u00e9", "cafe\u0301", "caf\u00e9", "cafe\u0301"},
+		{"e\u0302\u0323", "\u1ec7", "e\u0323\u0302", "\u1ec7", "e\u0323\u0302"}, // marks reordered by combining class
+		{"\ufb01ne", "\ufb01ne", "\ufb01ne", "fine", "fine"},                    // compatibility ligature
+		{"\u212b", "\u00c5", "A\u030a", "\u00c5", "A\u030a"},                    // angstrom sign is a canonical singleton
+		{"\ud55c\uad6d", "\ud55c\uad6d", "\u1112\u1161\u11ab\u1100\u116e\u11a8", "\ud55c\uad6d", "\u1112\u1161\u11ab\u1100\u116e\u11a8"},
+		{"\u1e9b\u0323", "\u1e9b\u0323", "\u017f\u0323\u0307", "\u1e69", "s\u0323\u0307"},
+	}
+	for _, test := range tests {
+		for i, f := range []Form{NFC, NFD, NFKC, NFKD} {
+			expected := []string{test.nfc, test.nfd, test.nfkc, test.nfkd}[i]
+			if s := f.Normalize(test.s); s != expected {
+				t.Errorf("%v(%+q): expected %+q, found %+q", f, test.s, expected, s)
+			}
+		}
+		for _, f := range []Form{NoForm, -1, NFKD + 1} {
+			if s := f.Normalize(test.s); s != test.s {
+				t.Errorf("%v(%+q): expected it unchanged, found %+q", f, test.s, s)
+			}
+		}
+	}
+
+	// composition undoes decomposition, in the Basic Multilingual Plane: x/text keys
+	// compositions by the low 16 bits of each rune, so it composes U+10041 and a mark
+	// as it does 'A' and the mark
+	for c := rune(0); c < 0x10000; c++ {
+		s := string(c) + "\u0327\u0301"
+		if nfd := NFD.Normalize(s); NFC.Normalize(nfd) != NFC.Normalize(s) || NFD.Normalize(NFC.Normalize(s)) != nfd {
+			t.Errorf("%U: NFC and NFD disagree", c)
+		}
+	}
+}
+
+func TestNormalizer(t *testing.T) {
+	text := "Naïve O'Brien's well-known CAFÉ—Ångström, ﬁne 3rd-rate"
+	tests := []struct {
+		name     string
+		n        Normalizer
+		expected []string
+	}{
+		{"default", Normalizer{}, []string{"naïve", "obriens", "well", "known", "café", "ångström", "ﬁne", "rd", "rate"}},
+		{"fold", Normalizer{Fold: true, Form: NFKC}, []string{"naive", "obriens", "well", "known", "cafe", "angstrom", "fine", "rd", "rate"}},
+		{"case", Normalizer{KeepCase: true, KeepApostrophes: true}, []string{"Naïve", "O'Brien's", "well", "known", "CAFÉ", "Ångström", "ﬁne", "rd", "rate"}},
+		{"join", Normalizer{Hyphens: JoinHyphens}, []string{"naïve", "obriens", "wellknown", "café", "ångström", "ﬁne", "rdrate"}},
+		{"keep", Normalizer{Hyphens: KeepHyphens}, []string{"naïve", "obriens", "well-known", "café", "ångström", "ﬁne", "rd-rate"}},
+		{"letters", Normalizer{Letters: true, Hyphens: KeepHyphens}, []string{"naïve", "obriens", "well-known", "café", "ångström", "ﬁne", "rd-rate"}},
+		{"cutset", Normalizer{Cutset: " ,"}, []string{"naïve", "obriens", "well", "known", "café—ångström", "ﬁne", "3rd", "rate"}},
+	}
+	for _, test := range tests {
+		var word []string
+		scanner := bufio.NewScanner(strings.NewReader(text))
+		scanner.Split(test.n.Split)
+		for scanner.Scan() {
+			word = append(word, test.n.Word(scanner.Text()))
+		}
+		if !reflect.DeepEqual(word, test.expected) {
+			t.Errorf("%s: expected %q, found %q", test.name, test.expected, word)
+		}
+	}
+
+	// words are found as they were normalized
+	g, err := NewGraph([]string{"cafe", "care", "core"}, &Options{Normalizer: Normalizer{Fold: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Ladder("Café", "CORE"); err != nil {
+		t.Errorf("expected a ladder from Café to CORE, found %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+package ladder
+
+/*
+ * source.go -- read text from files, standard input, compressed streams, and archives
+ */
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// leading bytes that identify compressed streams and archives
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh") // then a block size of '1' to '9' and bzip2Block
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	zipMagic   = []byte("PK\x03\x04")
+	tarMagic   = []byte("ustar") // at offset 257, in the header of the first member
+
+	// the magic of a bzip2 stream's first block, or of its end when it is empty
+	bzip2Block = [][]byte{
+		{0x31, 0x41, 0x59, 0x26, 0x53, 0x59},
+		{0x17, 0x72, 0x45, 0x38, 0x50, 0x90},
+	}
+)
+
+// whether header begins a bzip2 stream, checking the block size and the magic of the
+// first block as well as "BZh" so that text which happens to begin so is not taken
+// for one
+func isBzip2(header []byte) bool {
+	if len(header) < 10 || !bytes.HasPrefix(header, bzip2Magic) || header[3] < '1' || header[3] > '9' {
+		return false
+	}
+	for _, m := range bzip2Block {
+		if bytes.Equal(header[4:10], m) {
+			return true
+		}
+	}
+	return false
+}
+
+// A Decompressor returns a reader of the decompressed content of r, which begins
+// with the magic number it was registered for. The reader is closed when the
+// source is.
+type Decompressor func(r io.Reader) (io.ReadCloser, error)
+
+// decompressors registered for formats the standard library cannot read
+var (
+	decompressorMu sync.RWMutex
+	decompressor   []registered
+)
+
+type registered struct {
+	magic        []byte
+	decompressor Decompressor
+}
+
+// RegisterDecompressor registers a decompressor for streams beginning with magic,
+// of at most 8 bytes, such as Zstandard's 28 b5 2f fd, which the standard library
+// cannot read. Streams compressed by gzip and bzip2 are read without registering.
+func RegisterDecompressor(magic []byte, d Decompressor) {
+	if len(magic) < 1 || len(magic) > 8 {
+		panic("ladder: magic number of decompressor must be 1 to 8 bytes")
+	}
+	decompressorMu.Lock()
+	defer decompressorMu.Unlock()
+	decompressor = append(decompressor, registered{append([]byte(nil), magic...), d})
+}
+
+// the decompressor registered for a stream beginning with header, if any
+func findDecompressor(header []byte) Decompressor {
+	decompressorMu.RLock()
+	defer decompressorMu.RUnlock()
+	for _, r := range decompressor {
+		if bytes.HasPrefix(header, r.magic) {
+			return r.decompressor
+		}
+	}
+	return nil
+}
+
+// OpenSource opens the named file for reading, or standard input when the name is
+// "-", and decompresses it when it is compressed by gzip, bzip2, or a format with a
+// registered Decompressor, which are recognized by their content rather than the
+// name. A Zstandard stream without a registered decompressor is an error.
+func OpenSource(name string) (io.ReadCloser, error) {
+	return openSource(name)
+}
+
+func openSource(name string) (*source, error) {
+	s := &source{}
+	if name == "-" {
+		s.Reader = os.Stdin
+	} else {
+		file, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		s.Reader = file
+		s.closer = append(s.closer, file.Close)
+	}
+	if err := s.decompress(); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return s, nil
+}
+
+// A source is a reader of decompressed text and the means of closing each layer of
+// decompression, outermost first and the file last.
+type source struct {
+	io.Reader
+	closer     []func() error
+	compressed bool
+}
+
+// Close closes each layer, returning the first error.
+func (s *source) Close() error {
+	var err error
+	for i := len(s.closer) - 1; i >= 0; i-- {
+		if e := s.closer[i](); e != nil && err == nil {
+			err = e
+		}
+	}
+	s.closer = nil
+	return err
+}
+
+// replace the reader by its decompressed content for as long as it is compressed
+func (s *source) decompress() error {
+	for {
+		b := bufio.NewReader(s.Reader)
+		magic, _ := b.Peek(10)
+		d := findDecompressor(magic)
+		switch {
+		case bytes.HasPrefix(magic, gzipMagic):
+			z, err := gzip.NewReader(b)
+			if err != nil {
+				return err
+			}
+			s.Reader = z
+			s.closer = append(s.closer, z.Close)
+		case isBzip2(magic):
+			s.Reader = bzip2.NewReader(b)
+		case d != nil:
+			z, err := d(b)
+			if err != nil {
+				return err
+			}
+			s.Reader = z
+			s.closer = append(s.closer, z.Close)
+		case bytes.HasPrefix(magic, zstdMagic):
+			return errors.New("zstandard input needs a registered decompressor")
+		default:
+			s.Reader = b
+			return nil
+		}
+		s.compressed = true
+	}
+}
+
+// readMembers calls visit with the text of each regular file in the named source,
+// which may be a tar or zip archive, or be the only member itself. Sources and
+// members are decompressed as by OpenSource. Members are named for visit as the
+// source and the member's name within it, separated by a colon. An error closing
+// the source, such as that of a decompressor finding the stream cut short, is
+// returned when there is no earlier one.
+func readMembers(name string, visit func(member string, r io.Reader) error) (err error) {
+	s, err := openSource(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := s.Close(); e != nil && err == nil {
+			err = fmt.Errorf("%s: %v", name, e)
+		}
+	}()
+	b := bufio.NewReader(s)
+	header, _ := b.Peek(262)
+
+	switch {
+	case len(header) == 262 && bytes.Equal(header[257:262], tarMagic):
+		t := tar.NewReader(b)
+		for {
+			h, err := t.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+			if h.Typeflag != tar.TypeReg {
+				continue
+			}
+			if err := visitMember(name+":"+h.Name, t, visit); err != nil {
+				return err
+			}
+		}
+	case bytes.HasPrefix(header, zipMagic):
+		// zip's directory is at the end, so a stream must be read whole to find it
+		var z *zip.Reader
+		if name != "-" && !s.compressed {
+			file, err := zip.OpenReader(name)
+			if err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+			defer func() {
+				if e := file.Close(); e != nil && err == nil {
+					err = fmt.Errorf("%s: %v", name, e)
+				}
+			}()
+			z = &file.Reader
+		} else {
+			data, err := io.ReadAll(b)
+			if err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+			if z, err = zip.NewReader(bytes.NewReader(data), int64(len(data))); err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+		}
+		for _, f := range z.File {
+			if !f.Mode().IsRegular() {
+				continue
+			}
+			r, err := f.Open()
+			if err != nil {
+				return fmt.Errorf("%s:%s: %v", name, f.Name, err)
+			}
+			err = visitMember(name+":"+f.Name, r, visit)
+			r.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		if err := visit(name, b); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		return nil
+	}
+}
+
+// visit an archive member, decompressing it first if need be
+func visitMember(member string, r io.Reader, visit func(member string, r io.Reader) error) error {
+	s := &source{Reader: r}
+	err := s.decompress()
+	if err == nil {
+		err = visit(member, s.Reader)
+	}
+	if e := s.Close(); e != nil && err == nil {
+		err = e
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", member, err)
+	}
+	return nil
+}
+
+// the name of a file without the extension of any compression, such as "a.mtx"
+// for "a.mtx.gz", so that the extension describing its content can be found
+func uncompressedName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".gz", ".bz2", ".zst":
+		return strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
@@ -0,0 +1,188 @@
+package ladder
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func gzipped(text []byte) []byte {
+	var b bytes.Buffer
+	z := gzip.NewWriter(&b)
+	z.Write(text)
+	z.Close()
+	return b.Bytes()
+}
+
+// the magic number of a test format whose content follows it unchanged
+var testMagic = []byte("\x00ladder")
+
+// compress text with an external command, such as bzip2
+func compressed(t *testing.T, command string, text []byte) []byte {
+	if _, err := exec.LookPath(command); err != nil {
+		t.Skipf("%s not installed", command)
+	}
+	cmd := exec.Command(command, "-c")
+	cmd.Stdin = bytes.NewReader(text)
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("%s: %v", command, err)
+	}
+	return out
+}
+
+func TestReadSources(t *testing.T) {
+	first := []byte("Cold cord, card; ward.\n")
+	second := []byte("warm WORM word\n")
+	expected := []string{"card", "cold", "cord", "ward", "warm", "word", "worm"}
+
+	var tarred bytes.Buffer
+	tw := tar.NewWriter(&tarred)
+	for _, m := range []struct {
+		name string
+		body []byte
+	}{{"a.txt", first}, {"b.txt.gz", gzipped(second)}} {
+		tw.WriteHeader(&tar.Header{Name: m.name, Mode: 0644, Size: int64(len(m.body)), Typeflag: tar.TypeReg})
+		tw.Write(m.body)
+	}
+	tw.Close()
+
+	var zipped bytes.Buffer
+	zw := zip.NewWriter(&zipped)
+	zw.Create("words/") // directories are skipped
+	f, _ := zw.Create("words/a.txt")
+	f.Write(first)
+	f, _ = zw.Create("words/b.txt")
+	f.Write(second)
+	zw.Close()
+
+	plain := append(append([]byte{}, first...), second...)
+	dir := t.TempDir()
+	sources := []struct {
+		name string
+		data func(t *testing.T) []byte
+	}{
+		{"plain.txt", func(*testing.T) []byte { return plain }},
+		{"plain.txt.gz", func(*testing.T) []byte { return gzipped(plain) }},
+		{"archive.tar", func(*testing.T) []byte { return tarred.Bytes() }},
+		{"archive.tgz", func(*testing.T) []byte { return gzipped(tarred.Bytes()) }},
+		{"archive.zip", func(*testing.T) []byte { return zipped.Bytes() }},
+		{"archive.zip.gz", func(*testing.T) []byte { return gzipped(zipped.Bytes()) }},
+		{"plain.txt.bz2", func(t *testing.T) []byte { return compressed(t, "bzip2", plain) }},
+		{"plain.txt.test", func(*testing.T) []byte { return append(append([]byte{}, testMagic...), plain...) }},
+	}
+	RegisterDecompressor(testMagic, func(r io.Reader) (io.ReadCloser, error) {
+		_, err := io.CopyN(io.Discard, r, int64(len(testMagic)))
+		return io.NopCloser(r), err
+	})
+	for _, s := range sources {
+		t.Run(s.name, func(t *testing.T) {
+			name := filepath.Join(dir, s.name)
+			if err := os.WriteFile(name, s.data(t), 0644); err != nil {
+				t.Fatal(err)
+			}
+			word, err := ReadWords([]string{name}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(word, expected) {
+				t.Errorf("expected %v, read %v", expected, word)
+			}
+		})
+	}
+
+	// Zstandard needs a decompressor, which the library does not register
+	name := filepath.Join(dir, "plain.txt.zst")
+	if err := os.WriteFile(name, []byte{0x28, 0xb5, 0x2f, 0xfd, 0, 0, 0, 0}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenSource(name); err == nil {
+		t.Errorf("expected error reading zstandard without a decompressor")
+	}
+
+	// compressed graph files are recognized by the extension beneath the compression's
+	name = filepath.Join(dir, "square.mtx.gz")
+	mtx := "%%MatrixMarket matrix coordinate pattern symmetric\n4 4 4\n2 1\n3 2\n4 3\n4 1\n"
+	if err := os.WriteFile(name, gzipped([]byte(mtx)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	g, err := LoadGraph(name, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Len() != 4 || g.Edges() != 4 {
+		t.Errorf("square: expected 4 nodes and edges, read %d and %d", g.Len(), g.Edges())
+	}
+	if _, err := LoadGraph(name, "", &Options{Indel: true}); err == nil {
+		t.Errorf("square: expected error linking the words of a graph file")
+	}
+}
+
+// the magic number of a test format whose reader reads its content unchanged but
+// fails on closing, as a decompressor does finding its stream cut short
+var failMagic = []byte("\x00failing")
+
+func TestSourceCloseErrors(t *testing.T) {
+	RegisterDecompressor(failMagic, func(r io.Reader) (io.ReadCloser, error) {
+		_, err := io.CopyN(io.Discard, r, int64(len(failMagic)))
+		return failCloser{r}, err
+	})
+	dir := t.TempDir()
+	for _, f := range []struct {
+		name string
+		text string
+		load func(name string) error
+	}{
+		{"words.txt", "cold cord card ward warm\n", func(name string) error { _, err := ReadWords([]string{name}, nil); return err }},
+		{"costs.txt", "a e 1\n", func(name string) error { _, err := LoadCosts(name); return err }},
+		{"graph.txt", "a b\nb c\n", func(name string) error { _, err := LoadGraph(name, "", nil); return err }},
+	} {
+		name := filepath.Join(dir, f.name)
+		if err := os.WriteFile(name, []byte(f.text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.load(name); err != nil {
+			t.Errorf("%s: %v", f.name, err)
+		}
+		if err := os.WriteFile(name, append(append([]byte{}, failMagic...), f.text...), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.load(name); err == nil {
+			t.Errorf("%s: expected the error closing the stream", f.name)
+		}
+	}
+}
+
+type failCloser struct{ io.Reader }
+
+func (failCloser) Close() error { return errors.New("stream cut short") }
+
+// text beginning as bzip2's magic does is read as text unless a block follows
+func TestSourceBzip2Magic(t *testing.T) {
+	for _, text := range []string{"BZh", "BZh9 bzip", "BZh bzip", "BZh9 is not bzip2\n"} {
+		if isBzip2([]byte(text)) {
+			t.Errorf("%q: expected text, not bzip2", text)
+		}
+		name := filepath.Join(t.TempDir(), "words.txt")
+		if err := os.WriteFile(name, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+		word, err := ReadWords([]string{name}, nil)
+		if err != nil || len(word) == 0 || word[0] != "bzh" {
+			t.Errorf("%q: expected to read the text, read %v (%v)", text, word, err)
+		}
+	}
+	for _, text := range []string{"", "a", "cold cord card ward warm"} {
+		if z := compressed(t, "bzip2", []byte(text)); !isBzip2(z) {
+			t.Errorf("%q: expected a bzip2 header, found % x", text, z)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package ladder
+
+/*
+ * variation.go -- group words of any length by their "change one letter" variations
+ */
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// A variant is a word with one letter unknown, as "c?ld" is of "cold", or, for
+// insertion and deletion, a shorter word with an unknown letter inserted, as "c?ld"
+// is also of "cld". Words sharing a variation are linked. Sorting the variants of
+// every word by their keys brings each variation's words together without a map.
+type variant struct {
+	key  uint64 // the word's letters with the unknown one zero, or where it is in the word
+	word Index
+	pos  uint32 // position in runes of the unknown letter, with shortVariant for inserted ones
+}
+
+const shortVariant = 1 << 31
+
+// The variants of a list of words at one position of the unknown letter, with keys
+// packed into a uint64 when the alphabet and lengths allow, as for most
+// dictionaries, and otherwise compared as strings. Variants at different positions
+// never share a key, so each position can be found and sorted on its own.
+type variants struct {
+	v      []variant
+	spare  []variant // room for radix sorting v
+	word   []string
+	runes  Indexes  // length of each word in runes
+	key    []uint64 // each word's letters, width bits each, when packed
+	packed bool
+	fixed  bool // every word has the same length, with keys packed
+	indel  bool
+	widest int // length in runes of the longest word
+	lo     rune
+	width  uint
+}
+
+// Prepare to find the variants of the words, and also those of words one letter
+// shorter when indel is set.
+func newVariants(word []string, indel bool) variants {
+	// find the span of runes in use and the length of each word
+	s := variants{word: word, runes: make(Indexes, len(word)), indel: indel}
+	lo, hi := utf8.MaxRune, rune(0)
+	narrowest := math.MaxInt
+	for wn, w := range word {
+		n := 0
+		for _, r := range w {
+			if r < lo {
+				lo = r
+			}
+			if r > hi {
+				hi = r
+			}
+			n++
+		}
+		s.runes[wn] = Index(n)
+		s.widest = maxInt(s.widest, n)
+		narrowest = minInt(narrowest, n)
+	}
+
+	// code each rune as its offset from the lowest plus one, leaving zero unknown
+	s.lo, s.width = lo, uint(bits.Len32(uint32(hi-lo+1)))
+	if indel {
+		s.packed = hi >= lo && uint(s.widest+1)*s.width <= 64
+	} else {
+		s.packed = hi >= lo && uint(s.widest)*s.width <= 64
+	}
+	if s.packed {
+		s.key = make([]uint64, len(word))
+		for wn, w := range word {
+			n := uint(0)
+			for _, r := range w {
+				s.key[wn] |= uint64(r-lo+1) << (n * s.width)
+				n++
+			}
+		}
+		s.fixed = narrowest == s.widest // and so no word is shorter than another
+	}
+	return s
+}
+
+// Find the variants with the unknown letter at pos, sorted so that the words
+// sharing each variation are together, in order, with longer words before shorter
+// ones. The variants replace those of the previous position.
+func (s *variants) find(pos int) {
+	s.v = s.v[:0]
+	if s.packed {
+		s.addPacked(pos)
+	} else {
+		s.addMasked(pos)
+	}
+	if s.fixed {
+		s.radixSort()
+	} else {
+		sort.Sort(s)
+	}
+}
+
+// Sort variants with packed keys by key alone, a byte at a time from the lowest.
+// This is for words of one length, which have no shorter variants to order after
+// longer ones; the variants are added in word order and the sort is stable, so the
+// words sharing a key stay in order.
+func (s *variants) radixSort() {
+	if cap(s.spare) < len(s.v) {
+		s.spare = make([]variant, len(s.v))
+	}
+	from, to := s.v, s.spare[:len(s.v)]
+	for shift := uint(0); shift < uint(s.widest)*s.width; shift += 8 {
+		var count [257]int
+		for _, v := range from {
+			count[byte(v.key>>shift)+1]++
+		}
+		for i := 1; i < len(count); i++ {
+			count[i] += count[i-1]
+		}
+		for _, v := range from {
+			b := byte(v.key >> shift)
+			to[count[b]] = v
+			count[b]++
+		}
+		from, to = to, from
+	}
+	s.v, s.spare = from, to[:0]
+}
+
+// Add variants with keys of width bits per letter, the unknown one being zero. Keys
+// of words of different lengths never collide: the unknown letter is the only zero
+// below the highest nonzero letter, or just above it when the last is unknown.
+func (s *variants) addPacked(pos int) {
+	width, i := s.width, uint(pos)
+	for wn, key := range s.key {
+		n := int(s.runes[wn])
+		if pos < n {
+			s.v = append(s.v, variant{key &^ ((1<<width - 1) << (i * width)), Index(wn), uint32(pos)})
+		}
+		if s.indel && pos <= n && n < s.widest {
+			low := key & (1<<(i*width) - 1) // letters before the gap stay, those after move up
+			s.v = append(s.v, variant{low | (key&^low)<<width, Index(wn), uint32(pos) | shortVariant})
+		}
+	}
+}
+
+// Add variants whose keys are the word with the unknown letter replaced by a zero
+// byte, which is never part of another letter in UTF-8. Rather than building these
+// strings, a variant keeps the offset in bytes of the unknown letter and its width
+// (zero for inserted letters) and keys are compared in place.
+func (s *variants) addMasked(pos int) {
+	for wn, w := range s.word {
+		n := int(s.runes[wn])
+		full, short := pos < n, s.indel && pos <= n && n < s.widest // longest words have no longer ones to link
+		if !full && !short {
+			continue
+		}
+		at, i := len(w), 0 // offset in bytes of the rune at pos, if any
+		for j := range w {
+			if i == pos {
+				at = j
+				break
+			}
+			i++
+		}
+		if full {
+			_, size := utf8.DecodeRuneInString(w[at:])
+			s.v = append(s.v, variant{uint64(at)<<8 | uint64(size), Index(wn), uint32(pos)})
+		}
+		if short {
+			s.v = append(s.v, variant{uint64(at) << 8, Index(wn), uint32(pos) | shortVariant})
+		}
+	}
+}
+
+func (s *variants) Len() int      { return len(s.v) }
+func (s *variants) Swap(i, j int) { s.v[i], s.v[j] = s.v[j], s.v[i] }
+func (s *variants) Less(i, j int) bool {
+	a, b := s.v[i], s.v[j]
+	if c := s.compare(a, b); c != 0 {
+		return c < 0
+	}
+	if a.pos&shortVariant != b.pos&shortVariant {
+		return a.pos&shortVariant == 0 // longer words first
+	}
+	return a.word < b.word
+}
+
+// compare the keys of two variants
+func (s *variants) compare(a, b variant) int {
+	if s.packed {
+		switch {
+		case a.key < b.key:
+			return -1
+		case a.key > b.key:
+			return 1
+		}
+		return 0
+	}
+
+	// compare the words up to the first unknown letter, which is less than any other
+	w1, at1, width1 := s.word[a.word], int(a.key>>8), int(a.key&0xff)
+	w2, at2, width2 := s.word[b.word], int(b.key>>8), int(b.key&0xff)
+	n := minInt(at1, at2)
+	if c := strings.Compare(w1[:n], w2[:n]); c != 0 {
+		return c
+	}
+	switch {
+	case at1 < at2:
+		return -1
+	case at1 > at2:
+		return 1
+	}
+	return strings.Compare(w1[at1+width1:], w2[at2+width2:])
+}
+
+// Call link with each variation shared by two or more words, giving its position,
+// the words having it, and the shorter words that would with one letter inserted.
+func (s *variants) each(link func(pos int, long, short []variant)) {
+	for i := 0; i < len(s.v); {
+		j := i + 1
+		for j < len(s.v) && s.compare(s.v[i], s.v[j]) == 0 {
+			j++
+		}
+		run := s.v[i:j]
+		k := 0
+		for k < len(run) && run[k].pos&shortVariant == 0 {
+			k++
+		}
+		if k > 0 && len(run) > 1 { // a variation of at least one word, shared
+			link(int(run[0].pos), run[:k], run[k:])
+		}
+		i = j
+	}
+}
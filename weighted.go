@@ -0,0 +1,389 @@
+package ladder
+
+/*
+ * weighted.go -- cheapest rather than shortest ladders, by letter costs
+ */
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MAXCOST is the highest cost of a single step. Costs are small integers so that
+// cheapest paths can be found with a bucket queue rather than a priority queue.
+const MAXCOST = 255
+
+// Costs assign a cost to each step of a ladder according to its rule and letters,
+// so that the cheapest, rather than the shortest, ladders may be found. Steps not
+// covered by a cost matrix cost one, making cheapest ladders shortest ones.
+type Costs struct {
+	letter  map[[2]rune]int // costs between letters, '*' for any and '-' for none
+	anagram int
+}
+
+// ReadCosts reads a cost matrix, one entry per line, from r. Entries are of the form
+//
+//	a e 1       change a to e, or e to a
+//	a * 2       change a to any letter, or any letter to a
+//	* * 3       change any letter to any other
+//	- s 2       insert or delete s
+//	- * 4       insert or delete any letter
+//	anagram 5   rearrange all of the letters
+//
+// where the most specific entry applies, costs are in 0..MAXCOST, and everything
+// after a '#' is ignored.
+func ReadCosts(r io.Reader) (*Costs, error) {
+	c := &Costs{letter: make(map[[2]rune]int), anagram: 1}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		field := strings.Fields(text)
+		if len(field) == 0 {
+			continue
+		}
+
+		cost, err := strconv.Atoi(field[len(field)-1])
+		if err != nil || cost < 0 || cost > MAXCOST {
+			return nil, fmt.Errorf("line %d: cost %q must be an integer in 0..%d", line, field[len(field)-1], MAXCOST)
+		}
+		switch {
+		case len(field) == 2 && field[0] == "anagram":
+			c.anagram = cost
+		case len(field) == 3 && utf8.RuneCountInString(field[0]) == 1 && utf8.RuneCountInString(field[1]) == 1:
+			a, _ := utf8.DecodeRuneInString(field[0])
+			b, _ := utf8.DecodeRuneInString(field[1])
+			c.letter[[2]rune{a, b}] = cost
+			c.letter[[2]rune{b, a}] = cost
+		default:
+			return nil, fmt.Errorf("line %d: expected \"letter letter cost\" or \"anagram cost\"", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// LoadCosts reads a cost matrix from the named file, which may be compressed, as
+// by OpenSource.
+func LoadCosts(name string) (c *Costs, err error) {
+	file, err := OpenSource(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := file.Close(); e != nil && err == nil {
+			c, err = nil, fmt.Errorf("%s: %v", name, e)
+		}
+	}()
+
+	c, err = ReadCosts(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return c, nil
+}
+
+// Cost returns the cost of a step.
+func (c *Costs) Cost(s Step) int {
+	if c == nil {
+		return 1
+	}
+	pos := s.Pos()
+	switch s.Kind() {
+	case Substitute:
+		return c.lookup([]rune(s.From)[pos], []rune(s.To)[pos])
+	case Insert:
+		return c.lookup('-', []rune(s.To)[pos])
+	case Delete:
+		return c.lookup([]rune(s.From)[pos], '-')
+	case Link:
+		return 1
+	}
+	return c.anagram
+}
+
+// find the most specific cost between two letters (either may be '-' for none)
+func (c *Costs) lookup(a, b rune) int {
+	if cost, ok := c.letter[[2]rune{a, b}]; ok {
+		return cost
+	}
+	switch {
+	case a == '-':
+		if cost, ok := c.letter[[2]rune{'-', '*'}]; ok {
+			return cost
+		}
+	case b == '-':
+		if cost, ok := c.letter[[2]rune{'*', '-'}]; ok {
+			return cost
+		}
+	default:
+		for _, k := range [][2]rune{{a, '*'}, {'*', b}, {'*', '*'}} {
+			if cost, ok := c.letter[k]; ok {
+				return cost
+			}
+		}
+	}
+	return 1
+}
+
+// Find the cost of each link, aligned with adj.link and tag, and the highest cost of
+// any link.
+func findWeights(word []string, adj adjacency, tag []Edge, c *Costs) ([]uint8, int) {
+	highest := 0
+	weight := make([]uint8, len(adj.link))
+	for wn := range word {
+		for i := adj.first[wn]; i < adj.first[wn+1]; i++ {
+			cost := c.Cost(Step{word[wn], word[adj.link[i]], tag[i]})
+			weight[i] = uint8(cost)
+			highest = maxInt(highest, cost)
+		}
+	}
+	return weight, highest
+}
+
+// Sum the cost of one cheapest path between each pair of words. The results are the
+// number of word pairs and the summed costs.
+func sumAllSourcesWeightedPathsV1(word []string, adj adjacency, weight []uint8, highest int, component []Component) (int, int) {
+	var totalPairs, totalCosts int
+	if len(component) > 0 {
+		distance := make([]Index, len(word)) // cheapest cost to every node
+		parent := make([]Index, len(word))   // predecessor on cheapest path
+		done := make([]bool, len(word))      // state: has node been processed?
+		bucket := make([]Indexes, highest+1) // nodes by distance modulo buckets
+		for _, c := range component {
+			switch c.words {
+			case 1:
+			case 2:
+				w1, w2 := c.word[0], c.word[1]
+				totalPairs += 2
+				totalCosts += int(weight[adj.first[w1]]) + int(weight[adj.first[w2]])
+			default:
+				totalPairs += c.words * (c.words - 1)
+				for _, w := range c.word {
+					totalCosts += ssspDial(c.word, adj, weight, w, distance, parent, done, bucket)
+				}
+			}
+		}
+	}
+	return totalPairs, totalCosts
+}
+
+// Compute Single Source Shortest Paths by cost, rather than by number of steps, from
+// node w to every other node of its component. Edge costs are small integers, so
+// Dial's algorithm--Dijkstra's with a circular array of buckets indexed by distance,
+// one more bucket than the highest cost--replaces the priority queue. Return the
+// distance and parent of each node in distance and parent and the sum of the costs
+// of the cheapest paths.
+func ssspDial(word []Index, adj adjacency, weight []uint8, w Index, distance, parent []Index, done []bool, bucket []Indexes) int {
+	for _, wn := range word {
+		distance[wn] = INFINITY // not known to be rechable (can be graph or component)
+		done[wn] = false        // not yet settled at its cheapest distance
+	}
+	for i := range bucket {
+		bucket[i] = bucket[i][:0]
+	}
+	distance[w] = 0
+	parent[w] = w
+	bucket[0] = append(bucket[0], w)
+	pending := 1
+
+	// settle nodes in order of increasing distance from w
+	total := 0
+	for d := Index(0); pending > 0; d++ {
+		b := &bucket[int(d)%len(bucket)]
+		for len(*b) > 0 { // zero cost edges may add to the current bucket
+			n := (*b)[len(*b)-1]
+			*b = (*b)[:len(*b)-1]
+			pending--
+			if done[n] {
+				continue // stale entry, node already settled more cheaply
+			}
+			done[n] = true
+			total += int(d)
+			for i := adj.first[n]; i < adj.first[n+1]; i++ {
+				wn := adj.link[i]
+				if nd := d + Index(weight[i]); nd < distance[wn] {
+					distance[wn] = nd
+					parent[wn] = n
+					bn := &bucket[int(nd)%len(bucket)]
+					*bn = append(*bn, wn)
+					pending++
+				}
+			}
+		}
+	}
+	return total
+}
+
+func sumAllSourcesWeightedPathsV2(word []string, adj adjacency, weight []uint8, highest int, component []Component) (int, int) {
+	var i, j, totalPairs, totalCosts int
+	components := len(component)
+
+	// optimization -- skip parallel framework overhead when nothing but simple tasks
+	if components > 0 && component[0].words <= 16 {
+		return sumAllSourcesWeightedPathsV1(word, adj, weight, highest, component)
+	}
+
+	// solve large problems sequentially, using parallel workers within each
+	for ; i < components && component[i].words >= BREAKPOINT; i++ {
+		c := component[i]
+		totalPairs += c.words * (c.words - 1)
+		totalCosts += ssspWeightedWordsParallel(word, adj, weight, highest, c)
+	}
+
+	// solve medium problems in parallel, using a single worker for each
+	for j = i; j < components && component[j].words > 2; j++ {
+	}
+	if i < j {
+		pairCount, costCount := ssspWeightedComponentsParallel(word, adj, weight, highest, component[i:j])
+		totalPairs += pairCount
+		totalCosts += costCount
+		i = j
+	}
+
+	// solve small (nodes <= 2) problems directly
+	for ; i < len(component); i++ {
+		c := component[i]
+		switch {
+		case c.words == 1: // single aloof word with no solutions
+		case c.words == 2: // single pair of words with two one-step solutions (a->b and b->a)
+			w1, w2 := c.word[0], c.word[1]
+			totalPairs += 2
+			totalCosts += int(weight[adj.first[w1]]) + int(weight[adj.first[w2]])
+		default:
+			panic("internal error: small problem with more than 2 nodes")
+		}
+	}
+	return totalPairs, totalCosts
+}
+
+func ssspWeightedComponentsParallel(word []string, adj adjacency, weight []uint8, highest int, component []Component) (int, int) {
+	var totalPairs, totalCosts int
+	tasks := make(chan Component)
+	results := make(chan int)
+
+	// start workers
+	workers := MaxProcs
+	for k := 0; k < workers; k++ {
+		go func(id int, in chan Component, out chan int) {
+			distance := make([]Index, len(word))
+			parent := make([]Index, len(word))
+			done := make([]bool, len(word))
+			bucket := make([]Indexes, highest+1)
+
+			for c := range in {
+				total := 0
+				for _, w := range c.word {
+					total += ssspDial(c.word, adj, weight, w, distance, parent, done, bucket)
+				}
+				out <- total
+			}
+		}(k, tasks, results)
+	}
+
+	// dispatch tasks to workers
+	go func(out chan Component, component []Component) {
+		for _, c := range component {
+			out <- c
+		}
+		close(out)
+	}(tasks, component)
+
+	// harvest results from workers
+	for _ = range component {
+		totalCosts += <-results
+	}
+	close(results)
+
+	// determine number of pairs for these components
+	for _, c := range component {
+		totalPairs += c.words * (c.words - 1)
+	}
+	return totalPairs, totalCosts
+}
+
+func ssspWeightedWordsParallel(word []string, adj adjacency, weight []uint8, highest int, c Component) int {
+	tasks := make(chan Index)
+	results := make(chan int)
+
+	// start workers
+	workers := minInt(c.words, MaxProcs)
+	for i := 0; i < workers; i++ {
+		go func(id int, in chan Index, out chan int) {
+			distance := make([]Index, len(word))
+			parent := make([]Index, len(word))
+			done := make([]bool, len(word))
+			bucket := make([]Indexes, highest+1)
+			for w := range in {
+				out <- ssspDial(c.word, adj, weight, w, distance, parent, done, bucket)
+			}
+		}(i, tasks, results)
+	}
+
+	// start dispatcher
+	go func(out chan Index, c Component) {
+		for _, w := range c.word {
+			out <- w
+		}
+		close(out)
+	}(tasks, c)
+
+	// harvest results from workers
+	total := 0
+	for _ = range c.word {
+		total += <-results
+	}
+	close(results)
+	return total
+}
+
+// Find one cheapest ladder from word w1 to word w2, both in component c, and its cost.
+func findWeightedLadder(word []string, adj adjacency, weight []uint8, highest int, c Component, w1, w2 Index) (Indexes, int) {
+	distance := make([]Index, len(word))
+	parent := make([]Index, len(word))
+	done := make([]bool, len(word))
+	bucket := make([]Indexes, highest+1)
+	ssspDial(c.word, adj, weight, w1, distance, parent, done, bucket)
+	if !done[w2] {
+		return nil, 0 // not reachable (should not happen within a component)
+	}
+
+	// follow parent links from w2 back to w1
+	var ladder Indexes
+	for w := w2; w != w1; w = parent[w] {
+		ladder = append(ladder, w)
+	}
+	ladder = append(ladder, w1)
+	for i, j := 0, len(ladder)-1; i < j; i, j = i+1, j-1 {
+		ladder[i], ladder[j] = ladder[j], ladder[i]
+	}
+	return ladder, int(distance[w2])
+}
+
+// CheapestLadder returns one cheapest ladder of words from first to last under the
+// costs of a cost matrix, along with its total cost.
+func (g *Graph) CheapestLadder(first, last string, c *Costs) ([]string, int, error) {
+	w1, w2, cp, err := g.doublet(first, last)
+	if err != nil {
+		return nil, 0, err
+	}
+	weight, highest := findWeights(g.word, g.adj, g.tag, c)
+	ladder, cost := findWeightedLadder(g.word, g.adj, weight, highest, cp, w1, w2)
+	return g.words(ladder), cost, nil
+}
+
+// SumCheapestPaths sums the cost of one cheapest path between each ordered pair of
+// connected words under the costs of a cost matrix, returning the number of pairs
+// and the summed costs.
+func (g *Graph) SumCheapestPaths(c *Costs) (pairs, costs int) {
+	weight, highest := findWeights(g.word, g.adj, g.tag, c)
+	return sumAllSourcesWeightedPathsV2(g.word, g.adj, weight, highest, g.Components())
+}
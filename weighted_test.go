@@ -0,0 +1,159 @@
+package ladder
+
+import (
+	"container/heap"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// make every link cost k, or a random cost in 0..k when random is set
+func buildWeights(adj adjacency, k int, random bool) []uint8 {
+	rng := rand.New(rand.NewSource(1))
+	weight := make([]uint8, len(adj.link))
+	for i := range weight {
+		weight[i] = uint8(k)
+		if random {
+			weight[i] = uint8(rng.Intn(k + 1))
+		}
+	}
+	return weight
+}
+
+type Builder func() ([]string, []Indexes, []Component)
+
+var weightedGraphs = []struct {
+	name  string
+	build Builder
+}{
+	{"path", func() ([]string, []Indexes, []Component) { return buildPathGraph(50) }},
+	{"cycle", func() ([]string, []Indexes, []Component) { return buildCycleGraph(51) }},
+	{"wheel", func() ([]string, []Indexes, []Component) { return buildWheelGraph(40) }},
+	{"grid", func() ([]string, []Indexes, []Component) { return build2DGridGraph(9, 7) }},
+	{"bipartite", func() ([]string, []Indexes, []Component) { return buildCompleteBipartiteGraph(12, 5) }},
+	{"tree", func() ([]string, []Indexes, []Component) { return buildCompleteBinaryTree(5) }},
+}
+
+// with every edge costing k, cheapest paths are shortest paths costing k per step
+func TestWeightedUniform(t *testing.T) {
+	for _, g := range weightedGraphs {
+		for k := 1; k <= 3; k++ {
+			node, a, component := g.build()
+			adj := newAdjacency(a)
+			pairs, _, sum := sumAllSourcesShortestPathsV1(node, adj, component)
+			weight := buildWeights(adj, k, false)
+
+			pairs1, costs1 := sumAllSourcesWeightedPathsV1(node, adj, weight, k, component)
+			pairs2, costs2 := sumAllSourcesWeightedPathsV2(node, adj, weight, k, component)
+			if pairs1 != pairs || costs1 != k*sum || pairs2 != pairs || costs2 != k*sum {
+				t.Errorf("%s, k=%d: expected (%d, %d), computed V1 (%d, %d), V2 (%d, %d)",
+					g.name, k, pairs, k*sum, pairs1, costs1, pairs2, costs2)
+			}
+		}
+	}
+}
+
+// reference Dijkstra with a binary heap of (distance, node) entries
+type entry struct{ distance, node Index }
+type entryHeap []entry
+
+func (h entryHeap) Len() int            { return len(h) }
+func (h entryHeap) Less(i, j int) bool  { return h[i].distance < h[j].distance }
+func (h entryHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *entryHeap) Push(x interface{}) { *h = append(*h, x.(entry)) }
+func (h *entryHeap) Pop() interface{} {
+	x := (*h)[len(*h)-1]
+	*h = (*h)[:len(*h)-1]
+	return x
+}
+
+func dijkstra(adj adjacency, weight []uint8, w Index) []Index {
+	distance := make([]Index, adj.words())
+	for i := range distance {
+		distance[i] = INFINITY
+	}
+	distance[w] = 0
+	h := &entryHeap{{0, w}}
+	for h.Len() > 0 {
+		e := heap.Pop(h).(entry)
+		if e.distance > distance[e.node] {
+			continue // stale entry
+		}
+		for i := adj.first[e.node]; i < adj.first[e.node+1]; i++ {
+			wn := adj.link[i]
+			if d := e.distance + Index(weight[i]); d < distance[wn] {
+				distance[wn] = d
+				heap.Push(h, entry{d, wn})
+			}
+		}
+	}
+	return distance
+}
+
+// with random costs (including zero), Dial's algorithm agrees with Dijkstra's
+func TestWeightedRandom(t *testing.T) {
+	for _, g := range weightedGraphs {
+		for _, k := range []int{1, 4, 9} {
+			node, a, component := g.build()
+			adj := newAdjacency(a)
+			weight := buildWeights(adj, k, true)
+
+			sum := 0
+			for w := range node {
+				for _, d := range dijkstra(adj, weight, Index(w)) {
+					sum += int(d)
+				}
+			}
+
+			_, costs1 := sumAllSourcesWeightedPathsV1(node, adj, weight, k, component)
+			_, costs2 := sumAllSourcesWeightedPathsV2(node, adj, weight, k, component)
+			if costs1 != sum || costs2 != sum {
+				t.Errorf("%s, k=%d: expected %d, computed V1 %d, V2 %d", g.name, k, sum, costs1, costs2)
+			}
+		}
+	}
+}
+
+func TestCheapestLadder(t *testing.T) {
+	costs, err := ReadCosts(strings.NewReader(`
+		# vowels are cheap to change, everything else is dear
+		a e 1
+		a o 1
+		e o 1
+		* * 9
+		- s 2 # plurals are cheap
+		anagram 3
+	`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	word := []string{"bat", "bet", "bot", "cat", "cats", "cot", "tab"}
+	g, err := NewGraph(word, &Options{Indel: true, Anagram: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		from, to string
+		ladder   string
+		cost     int
+	}{
+		{"bat", "bot", "bat bot", 1},
+		{"bat", "cot", "bat bot cot", 10},
+		{"cats", "cot", "cats cat cot", 3},
+		{"bat", "tab", "bat tab", 3},
+	} {
+		l, cost, err := g.CheapestLadder(test.from, test.to, costs)
+		if err != nil || strings.Join(l, " ") != test.ladder || cost != test.cost {
+			t.Errorf("%s -> %s: expected %q costing %d, found %q costing %d (%v)",
+				test.from, test.to, test.ladder, test.cost, strings.Join(l, " "), cost, err)
+		}
+	}
+
+	for _, bad := range []string{"a e", "a e x", "a e 256", "ae i 1", "anagram"} {
+		if _, err := ReadCosts(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}